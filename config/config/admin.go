@@ -0,0 +1,66 @@
+package config
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Handler 返回管理配置的 http.Handler，可以挂载到内部的管理端口：
+//
+//	POST /config/reload  重新加载配置，返回 ReloadResult
+//	GET  /config         当前生效的配置，敏感配置项会被隐藏
+//	GET  /config/version 当前生效配置的版本（摘要）以及加载状态
+//
+// 挂载到其他路径下时需要使用 http.StripPrefix 去除前缀
+func (s *Store[T]) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/config/reload", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w, http.MethodPost)
+			return
+		}
+		result := s.ReloadWithResult()
+		code := http.StatusOK
+		if !result.Success {
+			code = http.StatusUnprocessableEntity
+		}
+		writeJSON(w, code, result)
+	})
+	mux.HandleFunc("/config", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			methodNotAllowed(w, http.MethodGet)
+			return
+		}
+		data, err := marshalMasked(s.Get(), FileTypeJSON, s.loader.opts)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(data)
+	})
+	mux.HandleFunc("/config/version", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			methodNotAllowed(w, http.MethodGet)
+			return
+		}
+		st := s.loader.Status()
+		v := struct {
+			Version string `json:"version"`
+			Status  Status `json:"status"`
+		}{st.Checksum, st}
+		writeJSON(w, http.StatusOK, v)
+	})
+	return mux
+}
+
+func methodNotAllowed(w http.ResponseWriter, allow string) {
+	w.Header().Set("Allow", allow)
+	http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
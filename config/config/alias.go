@@ -0,0 +1,44 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrAliasConflict 新键与其别名（旧键）同时出现在配置中
+var ErrAliasConflict = errors.New("config key conflicts with its alias")
+
+// resolveAlias 查找字段在配置映射中实际使用的键，key 为空表示未设置
+// 通过 `aliases` 标签声明的旧键同样可以设置字段，此时 aliased 为 true 并产生告警
+// `deprecated` 标签为告警信息，若字段没有声明别名，则表示该字段本身已废弃
+func resolveAlias(m map[string]interface{}, f field, path string) (key string, aliased bool, warning string, err error) {
+	key, set := lookupKey(m, f.key, f.fold)
+	for _, a := range f.aliases {
+		k, ok := lookupKey(m, a, f.fold)
+		if !ok || k == key {
+			continue
+		}
+		if set || aliased {
+			return "", false, "", fmt.Errorf("%w: %q and %q", ErrAliasConflict, joinPath(path, key), joinPath(path, k))
+		}
+		key, aliased = k, true
+	}
+
+	switch {
+	case aliased:
+		warning = deprecation(joinPath(path, key), joinPath(path, f.key), f.deprecated)
+	case set && f.deprecated != "" && len(f.aliases) == 0:
+		warning = deprecation(joinPath(path, key), "", f.deprecated)
+	}
+	return key, aliased, warning, nil
+}
+
+func deprecation(key, replacement, msg string) string {
+	switch {
+	case msg != "":
+		return fmt.Sprintf("config key %q is deprecated: %s", key, msg)
+	case replacement != "":
+		return fmt.Sprintf("config key %q is deprecated, use %q instead", key, replacement)
+	}
+	return fmt.Sprintf("config key %q is deprecated", key)
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type AliasConfig struct {
+	Username string
+	Password string
+	Timeout  int `deprecated:"timeout is ignored, remove it"`
+	Server   struct {
+		URL string `yaml:"url" json:"url" aliases:"endpoint_url,endpointURL" deprecated:"use server.url instead"`
+	}
+}
+
+func TestLoadConfigWithAliases(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		typ      FileType
+		warning  string
+	}{
+		{
+			name:     "yaml",
+			filename: "testdata/alias.yaml",
+			typ:      FileTypeYAML,
+			warning:  `config key "server.endpoint_url" is deprecated: use server.url instead`,
+		},
+		{
+			name:     "json",
+			filename: "testdata/alias.json",
+			typ:      FileTypeJSON,
+			warning:  `config key "server.endpointURL" is deprecated: use server.url instead`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var handled []string
+			l := NewLoader(tt.filename, tt.typ, WithWarningHandler(func(msg string) {
+				handled = append(handled, msg)
+			}))
+
+			var cfg AliasConfig
+			err := l.Load(&cfg)
+			assert.NoError(t, err)
+			assert.Equal(t, "user", cfg.Username)
+			assert.Equal(t, "https://jianghushinian.cn/", cfg.Server.URL)
+			assert.Equal(t, []string{tt.warning}, l.Warnings())
+			assert.Equal(t, l.Warnings(), handled)
+		})
+	}
+}
+
+func TestLoadConfigWithDeprecatedField(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "config.yaml")
+	_ = os.WriteFile(filename, []byte("timeout: 3\nserver:\n  url: https://jianghushinian.cn/\n"), 0644)
+
+	l := NewLoader(filename, FileTypeYAML)
+	var cfg AliasConfig
+	err := l.Load(&cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, cfg.Timeout)
+	assert.Equal(t, []string{`config key "timeout" is deprecated: timeout is ignored, remove it`}, l.Warnings())
+}
+
+func TestLoadConfigAliasConflict(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "config.yaml")
+	_ = os.WriteFile(filename, []byte("server:\n  url: https://a/\n  endpoint_url: https://b/\n"), 0644)
+
+	var cfg AliasConfig
+	err := LoadYAMLConfig(filename, &cfg)
+	assert.ErrorIs(t, err, ErrAliasConflict)
+	assert.EqualError(t, err, `config key conflicts with its alias: "server.url" and "server.endpoint_url"`)
+}
@@ -0,0 +1,427 @@
+// Package cli 实现 gokit-config 命令，用于校验、转换、渲染以及比较配置文件
+//
+// 需要按照配置结构体校验配置文件时，可以在自定义的 main 包中注册配置结构体后调用 Run：
+//
+//	func main() {
+//		config.RegisterSchema("app", func() interface{} { return new(AppConfig) })
+//		os.Exit(cli.Run(os.Args[1:], os.Stdout, os.Stderr))
+//	}
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/jianghushinian/gokit/config/config"
+)
+
+const usage = `Usage: gokit-config <command> [flags] <file>...
+
+Commands:
+  validate  校验配置文件，指定 -schema 时按照注册的配置结构体校验
+  convert   在 YAML、JSON、TOML 格式之间转换配置文件
+  render    输出叠加环境变量、-set 覆盖值后最终生效的配置，敏感配置项会被隐藏
+  diff      比较两个配置文件的差异，存在差异时退出码为 1
+  lint      按照注册的配置结构体检查未使用的键以及未设置的字段，以 JSON 格式输出，存在问题时退出码为 1
+  schemas   列出已注册的配置结构体
+
+Run 'gokit-config <command> -h' for more information on a command.
+`
+
+// Run 执行 gokit-config 命令，返回进程退出码
+func Run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	cmds := map[string]func(args []string, stdout, stderr io.Writer) error{
+		"validate": runValidate,
+		"convert":  runConvert,
+		"render":   runRender,
+		"diff":     runDiff,
+		"lint":     runLint,
+		"schemas":  runSchemas,
+	}
+	cmd, ok := cmds[args[0]]
+	if !ok {
+		if args[0] == "-h" || args[0] == "-help" || args[0] == "help" {
+			fmt.Fprint(stdout, usage)
+			return 0
+		}
+		fmt.Fprintf(stderr, "gokit-config: unknown command %q\n\n%s", args[0], usage)
+		return 2
+	}
+
+	err := cmd(args[1:], stdout, stderr)
+	var exitErr *exitError
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.As(err, &exitErr):
+		return exitErr.code
+	}
+	fmt.Fprintf(stderr, "gokit-config %s: %v\n", args[0], err)
+	return 1
+}
+
+// exitError 以指定退出码结束命令，错误信息已经输出
+type exitError struct {
+	code int
+}
+
+func (e *exitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
+// loadFlags 加载配置文件的公共参数
+type loadFlags struct {
+	schema    string
+	typ       string
+	naming    string
+	envPrefix string
+	overrides overrides
+}
+
+func (f *loadFlags) register(fs *flag.FlagSet, overlay bool) {
+	fs.StringVar(&f.schema, "schema", "", "按照注册的配置结构体加载配置文件")
+	fs.StringVar(&f.typ, "type", "", "配置文件类型：yaml、json、toml、dir，默认根据文件扩展名推断")
+	fs.StringVar(&f.naming, "naming", "default", "配置键命名风格：default、exact、snake_case、camelCase、kebab-case")
+	if overlay {
+		fs.StringVar(&f.envPrefix, "env-prefix", "", "使用指定前缀的环境变量覆盖配置，需要指定 -schema")
+		fs.Var(&f.overrides, "set", "使用 key=value 覆盖配置，可以指定多次")
+	}
+}
+
+func (f *loadFlags) options() ([]config.Option, error) {
+	naming, err := parseNaming(f.naming)
+	if err != nil {
+		return nil, err
+	}
+	// 命令行工具加载的配置总是需要校验
+	opts := []config.Option{config.WithNaming(naming), config.WithValidation()}
+	if f.envPrefix != "" {
+		if f.schema == "" {
+			return nil, errors.New("-env-prefix requires -schema")
+		}
+		opts = append(opts, config.WithEnvPrefix(f.envPrefix))
+	}
+	if len(f.overrides) > 0 {
+		opts = append(opts, config.WithOverrides(f.overrides))
+	}
+	return opts, nil
+}
+
+// load 加载配置文件，未指定 -schema 时加载为映射
+func (f *loadFlags) load(filename string, stderr io.Writer) (cfg interface{}, typ config.FileType, err error) {
+	if typ, err = fileType(filename, f.typ); err != nil {
+		return nil, typ, err
+	}
+	opts, err := f.options()
+	if err != nil {
+		return nil, typ, err
+	}
+
+	if f.schema != "" {
+		newFn, ok := config.LookupSchema(f.schema)
+		if !ok {
+			return nil, typ, fmt.Errorf("unknown schema %q, registered schemas: %s", f.schema, strings.Join(config.Schemas(), ", "))
+		}
+		cfg = newFn()
+	} else {
+		cfg = &map[string]interface{}{}
+	}
+
+	l := config.NewLoader(filename, typ, opts...)
+	if err = l.Load(cfg); err != nil {
+		return nil, typ, fmt.Errorf("%s: %w", filename, err)
+	}
+	for _, w := range l.Warnings() {
+		fmt.Fprintf(stderr, "%s: warning: %s\n", filename, w)
+	}
+	return cfg, typ, nil
+}
+
+func runValidate(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("validate", "[flags] <file>...", stderr)
+	var lf loadFlags
+	lf.register(fs, false)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return &exitError{code: 2}
+	}
+
+	failed := false
+	for _, filename := range fs.Args() {
+		if _, _, err := lf.load(filename, stderr); err != nil {
+			fmt.Fprintln(stderr, err)
+			failed = true
+			continue
+		}
+		fmt.Fprintf(stdout, "%s: ok\n", filename)
+	}
+	if failed {
+		return &exitError{code: 1}
+	}
+	return nil
+}
+
+func runConvert(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("convert", "-to <type> [flags] <file>", stderr)
+	var (
+		from = fs.String("from", "", "源文件类型：yaml、json、toml，默认根据文件扩展名推断")
+		to   = fs.String("to", "", "目标文件类型：yaml、json、toml")
+		out  = fs.String("o", "", "输出文件，默认输出到标准输出")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 || *to == "" {
+		fs.Usage()
+		return &exitError{code: 2}
+	}
+
+	filename := fs.Arg(0)
+	fromType, err := fileType(filename, *from)
+	if err != nil {
+		return err
+	}
+	toType, err := config.ParseFileType(*to)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	if data, err = config.Convert(data, fromType, toType); err != nil {
+		return fmt.Errorf("%s: %w", filename, err)
+	}
+	return write(*out, pretty(data, toType), stdout)
+}
+
+func runRender(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("render", "[flags] <file>", stderr)
+	var lf loadFlags
+	lf.register(fs, true)
+	format := fs.String("format", "", "输出格式：yaml、json、toml，默认与配置文件类型一致")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return &exitError{code: 2}
+	}
+
+	cfg, typ, err := lf.load(fs.Arg(0), stderr)
+	if err != nil {
+		return err
+	}
+	if typ == config.FileTypeDir {
+		typ = config.FileTypeYAML
+	}
+	if *format != "" {
+		if typ, err = config.ParseFileType(*format); err != nil {
+			return err
+		}
+	}
+	opts, _ := lf.options()
+	data, err := config.MarshalMasked(cfg, typ, opts...)
+	if err != nil {
+		return err
+	}
+	return write("", pretty(data, typ), stdout)
+}
+
+func runDiff(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("diff", "[flags] <old> <new>", stderr)
+	var lf loadFlags
+	lf.register(fs, false)
+	asJSON := fs.Bool("json", false, "以 JSON 格式输出差异")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return &exitError{code: 2}
+	}
+
+	var cfgs [2]interface{}
+	for i, filename := range fs.Args() {
+		var err error
+		if cfgs[i], _, err = lf.load(filename, stderr); err != nil {
+			return err
+		}
+	}
+	opts, _ := lf.options()
+	changes, err := config.Diff(cfgs[0], cfgs[1], opts...)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err = enc.Encode(changes); err != nil {
+			return err
+		}
+	} else {
+		for _, c := range changes {
+			fmt.Fprintln(stdout, c)
+		}
+	}
+	if len(changes) > 0 {
+		return &exitError{code: 1}
+	}
+	return nil
+}
+
+func runLint(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("lint", "-schema <name> [flags] <file>...", stderr)
+	var lf loadFlags
+	lf.register(fs, false)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 || lf.schema == "" {
+		fs.Usage()
+		return &exitError{code: 2}
+	}
+	newFn, ok := config.LookupSchema(lf.schema)
+	if !ok {
+		return fmt.Errorf("unknown schema %q, registered schemas: %s", lf.schema, strings.Join(config.Schemas(), ", "))
+	}
+	opts, err := lf.options()
+	if err != nil {
+		return err
+	}
+
+	reports := make([]*config.LintReport, 0, fs.NArg())
+	ok = true
+	for _, filename := range fs.Args() {
+		typ, err := fileType(filename, lf.typ)
+		if err != nil {
+			return err
+		}
+		r, err := config.Lint(filename, newFn(), typ, opts...)
+		if err != nil {
+			return fmt.Errorf("%s: %w", filename, err)
+		}
+		reports = append(reports, r)
+		ok = ok && r.OK()
+	}
+
+	enc := json.NewEncoder(stdout)
+	enc.SetIndent("", "  ")
+	if err = enc.Encode(reports); err != nil {
+		return err
+	}
+	if !ok {
+		return &exitError{code: 1}
+	}
+	return nil
+}
+
+func runSchemas(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("schemas", "", stderr)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	for _, name := range config.Schemas() {
+		fmt.Fprintln(stdout, name)
+	}
+	return nil
+}
+
+func newFlagSet(name, args string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: gokit-config %s %s\n\nFlags:\n", name, args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+func fileType(filename, typ string) (config.FileType, error) {
+	if typ != "" {
+		return config.ParseFileType(typ)
+	}
+	return config.FileTypeOf(filename)
+}
+
+func parseNaming(s string) (config.Naming, error) {
+	for _, n := range []config.Naming{
+		config.NamingDefault,
+		config.NamingExact,
+		config.NamingSnakeCase,
+		config.NamingCamelCase,
+		config.NamingKebabCase,
+	} {
+		if n.String() == s {
+			return n, nil
+		}
+	}
+	return 0, fmt.Errorf("unsupported naming: %s", s)
+}
+
+// pretty 格式化 JSON 并确保内容以换行结尾
+func pretty(data []byte, typ config.FileType) []byte {
+	if typ == config.FileTypeJSON {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, data, "", "  "); err == nil {
+			data = buf.Bytes()
+		}
+	}
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		data = append(data, '\n')
+	}
+	return data
+}
+
+func write(filename string, data []byte, stdout io.Writer) error {
+	if filename != "" {
+		return os.WriteFile(filename, data, 0644)
+	}
+	_, err := stdout.Write(data)
+	return err
+}
+
+// overrides 可以多次指定的 -set key=value 参数
+type overrides map[string]string
+
+func (o *overrides) String() string {
+	if o == nil {
+		return ""
+	}
+	pairs := make([]string, 0, len(*o))
+	for k, v := range *o {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (o *overrides) Set(s string) error {
+	k, v, ok := strings.Cut(s, "=")
+	if !ok || k == "" {
+		return fmt.Errorf("invalid override %q, expected key=value", s)
+	}
+	if *o == nil {
+		*o = make(overrides)
+	}
+	(*o)[k] = v
+	return nil
+}
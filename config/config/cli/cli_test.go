@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jianghushinian/gokit/config/config"
+)
+
+type appConfig struct {
+	Username string
+	Password string
+	Server   struct {
+		Endpoint string
+		Port     int
+	}
+}
+
+func (c *appConfig) Validate() error {
+	if c.Server.Port == 0 {
+		return os.ErrInvalid
+	}
+	return nil
+}
+
+func init() {
+	config.RegisterSchema("app", func() interface{} { return new(appConfig) })
+}
+
+func run(args ...string) (code int, stdout, stderr string) {
+	var out, errOut bytes.Buffer
+	code = Run(args, &out, &errOut)
+	return code, out.String(), errOut.String()
+}
+
+func TestValidate(t *testing.T) {
+	code, stdout, _ := run("validate", "../testdata/config.yaml", "../testdata/config.json")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "../testdata/config.yaml: ok\n../testdata/config.json: ok\n", stdout)
+
+	code, _, stderr := run("validate", "-schema", "app", "../testdata/config.yaml")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "validate config: invalid argument")
+
+	code, _, stderr = run("validate", "-schema", "unknown", "../testdata/config.yaml")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, `unknown schema "unknown"`)
+}
+
+func TestConvert(t *testing.T) {
+	code, stdout, _ := run("convert", "-to", "json", "../testdata/config.toml")
+	assert.Equal(t, 0, code)
+	assert.JSONEq(t, `{"username": "user", "password": "pass", "server": {"endpoint": "https://jianghushinian.cn/"}}`, stdout)
+
+	out := filepath.Join(t.TempDir(), "config.yaml")
+	code, _, _ = run("convert", "-to", "yaml", "-o", out, "../testdata/config.json")
+	assert.Equal(t, 0, code)
+	var cfg map[string]interface{}
+	assert.NoError(t, config.LoadYAMLConfig(out, &cfg))
+	assert.Equal(t, "user", cfg["username"])
+
+	code, _, _ = run("convert", "../testdata/config.json")
+	assert.Equal(t, 2, code)
+}
+
+func TestRender(t *testing.T) {
+	t.Setenv("APP_USERNAME", "env-user")
+
+	code, stdout, stderr := run("render", "-schema", "app", "-env-prefix", "APP",
+		"-set", "server.port=8080", "-format", "json", "../testdata/config.yaml")
+	assert.Equal(t, 0, code, stderr)
+	assert.JSONEq(t, `{
+		"Username": "env-user",
+		"Password": "******",
+		"Server": {"Endpoint": "https://jianghushinian.cn/", "Port": 8080}
+	}`, stdout)
+
+	code, stdout, _ = run("render", "-set", "server.port=8080", "../testdata/config.yaml")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stdout, "password: '******'")
+	assert.Contains(t, stdout, "port: 8080")
+}
+
+func TestDiff(t *testing.T) {
+	code, stdout, _ := run("diff", "../testdata/config.yaml", "../testdata/config.json")
+	assert.Equal(t, 0, code)
+	assert.Empty(t, stdout)
+
+	code, stdout, _ = run("diff", "../testdata/config.yaml", "../testdata/alias.yaml")
+	assert.Equal(t, 1, code)
+	assert.Equal(t, "- server.endpoint: https://jianghushinian.cn/\n+ server.endpoint_url: https://jianghushinian.cn/\n", stdout)
+}
+
+func TestLint(t *testing.T) {
+	code, stdout, _ := run("lint", "-schema", "app", "../testdata/config.yaml")
+	assert.Equal(t, 1, code)
+	assert.JSONEq(t, `[{
+		"file": "../testdata/config.yaml",
+		"unusedKeys": [],
+		"unsetFields": ["server.port"]
+	}]`, stdout)
+
+	code, stdout, _ = run("lint", "-schema", "app", "../testdata/alias.yaml")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stdout, `"server.endpoint_url"`)
+
+	code, _, _ = run("lint", "../testdata/config.yaml")
+	assert.Equal(t, 2, code)
+}
+
+func TestRunUnknownCommand(t *testing.T) {
+	code, _, stderr := run("unknown")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, `unknown command "unknown"`)
+}
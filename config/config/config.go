@@ -1,13 +1,12 @@
 package config
 
 import (
-	"encoding/json"
-	"errors"
 	"flag"
 	"fmt"
 	"os"
-
-	"gopkg.in/yaml.v3"
+	"path/filepath"
+	"reflect"
+	"strings"
 )
 
 var (
@@ -17,36 +16,64 @@ var (
 
 type FileType int
 
+// 文件类型常量保持为无类型常量，兼容将其作为 int 使用的调用方
 const (
 	FileTypeYAML = iota
 	FileTypeJSON
+	FileTypeTOML
+	// FileTypeDir 一个文件对应一个键的目录，如 Kubernetes 以卷方式挂载的 ConfigMap、Secret
+	FileTypeDir
 )
 
-func LoadConfig(filename string, cfg interface{}, typ FileType) error {
-	data, err := os.ReadFile(filename)
-	if err != nil {
-		return fmt.Errorf("ReadFile: %v", err)
-	}
-	switch typ {
+func (t FileType) String() string {
+	switch t {
 	case FileTypeYAML:
-		return yaml.Unmarshal(data, cfg)
+		return "yaml"
 	case FileTypeJSON:
-		return json.Unmarshal(data, cfg)
+		return "json"
+	case FileTypeTOML:
+		return "toml"
+	case FileTypeDir:
+		return "dir"
 	}
-	return errors.New("unsupported file type")
+	return "unknown"
 }
 
-func DumpConfig(filename string, cfg interface{}, typ FileType) error {
-	var (
-		data []byte
-		err  error
-	)
-	switch typ {
-	case FileTypeYAML:
-		data, err = yaml.Marshal(cfg)
-	case FileTypeJSON:
-		data, err = json.Marshal(cfg)
+// ParseFileType 解析文件类型名称，如 yaml、yml、json、toml、dir
+func ParseFileType(s string) (FileType, error) {
+	switch strings.ToLower(s) {
+	case "yaml", "yml":
+		return FileTypeYAML, nil
+	case "json":
+		return FileTypeJSON, nil
+	case "toml":
+		return FileTypeTOML, nil
+	case "dir":
+		return FileTypeDir, nil
 	}
+	return 0, fmt.Errorf("unsupported file type: %s", s)
+}
+
+// FileTypeOf 根据文件扩展名推断文件类型，目录对应 FileTypeDir
+func FileTypeOf(filename string) (FileType, error) {
+	if info, err := os.Stat(filename); err == nil && info.IsDir() {
+		return FileTypeDir, nil
+	}
+	return ParseFileType(strings.TrimPrefix(filepath.Ext(filename), "."))
+}
+
+// LoadConfig 加载 typ 格式的配置文件 filename 到 cfg，与 Load 相同，参数指定的文件及其类型优先于 WithFile、WithFileType 选项
+func LoadConfig(filename string, cfg interface{}, typ FileType, opts ...Option) error {
+	return loadInto(cfg, append(opts[:len(opts):len(opts)], WithFile(filename), WithFileType(typ)))
+}
+
+// MarshalConfig 按照 typ 格式编码配置
+func MarshalConfig(cfg interface{}, typ FileType, opts ...Option) ([]byte, error) {
+	return marshal(cfg, typ, newOptions(opts))
+}
+
+func DumpConfig(filename string, cfg interface{}, typ FileType, opts ...Option) error {
+	data, err := marshal(cfg, typ, newOptions(opts))
 	if err != nil {
 		return err
 	}
@@ -59,6 +86,26 @@ func DumpConfig(filename string, cfg interface{}, typ FileType) error {
 	return err
 }
 
+func marshal(cfg interface{}, typ FileType, o options) ([]byte, error) {
+	data, err := marshalTree(cfg, typ)
+	if err != nil || o.naming == NamingDefault {
+		return data, err
+	}
+
+	t := reflect.TypeOf(cfg)
+	if t == nil || indirectType(t).Kind() != reflect.Struct {
+		return data, nil
+	}
+	tree, err := unmarshalTree(data, typ)
+	if err != nil {
+		return nil, err
+	}
+	if err = denormalize(tree, t, typ, o.naming); err != nil {
+		return nil, err
+	}
+	return marshalTree(tree, typ)
+}
+
 func init() {
 	// 默认从环境变量加载配置
 	_ = flag.Set("c", os.Getenv("CONFIG_PATH"))
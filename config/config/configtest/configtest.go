@@ -0,0 +1,149 @@
+// Package configtest 提供测试依赖配置的代码时使用的辅助函数
+// 所有函数都不会修改全局的命令行参数以及进程的环境变量，可以在并行测试中使用
+package configtest
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+
+	"github.com/jianghushinian/gokit/config/config"
+)
+
+// WriteFile 将 cfg（结构体或映射）按照 typ 格式写入测试的临时目录，返回文件路径
+// typ 为 config.FileTypeDir 时会写入一个文件对应一个键的目录，嵌套的键使用 "__" 连接
+func WriteFile(t testing.TB, cfg interface{}, typ config.FileType, opts ...config.Option) string {
+	t.Helper()
+	if typ == config.FileTypeDir {
+		return writeDir(t, cfg, opts...)
+	}
+
+	filename := filepath.Join(t.TempDir(), "config."+typ.String())
+	if err := config.DumpConfig(filename, cfg, typ, opts...); err != nil {
+		t.Fatalf("configtest: write config: %v", err)
+	}
+	return filename
+}
+
+// WriteString 将配置文档 doc 写入测试的临时目录，返回文件路径
+func WriteString(t testing.TB, doc string, typ config.FileType) string {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "config."+typ.String())
+	if err := os.WriteFile(filename, []byte(doc), 0644); err != nil {
+		t.Fatalf("configtest: write config: %v", err)
+	}
+	return filename
+}
+
+func writeDir(t testing.TB, cfg interface{}, opts ...config.Option) string {
+	t.Helper()
+	data, err := config.MarshalConfig(cfg, config.FileTypeYAML, opts...)
+	if err != nil {
+		t.Fatalf("configtest: write config: %v", err)
+	}
+	var tree map[string]interface{}
+	if err = yaml.Unmarshal(data, &tree); err != nil {
+		t.Fatalf("configtest: write config: %v", err)
+	}
+
+	dir := t.TempDir()
+	files := make(map[string]string)
+	flatten(tree, nil, files)
+	for name, content := range files {
+		if err = os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("configtest: write config: %v", err)
+		}
+	}
+	return dir
+}
+
+func flatten(v interface{}, keys []string, files map[string]string) {
+	if m, ok := v.(map[string]interface{}); ok {
+		for k, e := range m {
+			flatten(e, append(append([]string{}, keys...), k), files)
+		}
+		return
+	}
+	if s, ok := v.(string); ok {
+		files[strings.Join(keys, "__")] = s
+		return
+	}
+	data, _ := yaml.Marshal(v)
+	files[strings.Join(keys, "__")] = strings.TrimSuffix(string(data), "\n")
+}
+
+// Env 返回使用 vars 作为环境变量的选项，需要配合 config.WithEnvPrefix 使用
+// 与 t.Setenv 不同，不会修改进程的环境变量，因此可以在并行测试中使用
+func Env(vars map[string]string) config.Option {
+	env := make(map[string]string, len(vars))
+	for k, v := range vars {
+		env[k] = v
+	}
+	return config.WithEnvLookup(func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	})
+}
+
+// NewLoader 将配置文档 doc 写入测试的临时目录，并创建加载该文件的配置加载器
+func NewLoader(t testing.TB, doc string, typ config.FileType, opts ...config.Option) *config.Loader {
+	t.Helper()
+	return config.NewLoader(WriteString(t, doc, typ), typ, opts...)
+}
+
+// Load 加载配置文档 doc，返回类型为 T 的配置
+func Load[T any](t testing.TB, doc string, typ config.FileType, opts ...config.Option) (*T, error) {
+	t.Helper()
+	cfg := new(T)
+	if err := NewLoader(t, doc, typ, opts...).Load(cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// AssertLoads 断言加载配置文档 doc 得到的配置与 want 相等
+func AssertLoads[T any](t testing.TB, doc string, typ config.FileType, want T, opts ...config.Option) bool {
+	t.Helper()
+	cfg, err := Load[T](t, doc, typ, opts...)
+	if !assert.NoError(t, err) {
+		return false
+	}
+	return assert.Equal(t, want, *cfg)
+}
+
+// AssertLoadError 断言加载配置文档 doc 失败
+// target 不为 nil 时，断言错误链中包含 target
+func AssertLoadError[T any](t testing.TB, doc string, typ config.FileType, target error, opts ...config.Option) bool {
+	t.Helper()
+	_, err := Load[T](t, doc, typ, opts...)
+	if target == nil {
+		return assert.Error(t, err)
+	}
+	return assert.ErrorIs(t, err, target)
+}
+
+// AssertValidationError 断言加载配置文档 doc 时校验失败
+// target 不为 nil 时，断言校验错误的错误链中包含 target，加载时总是会开启校验
+func AssertValidationError[T any](t testing.TB, doc string, typ config.FileType, target error, opts ...config.Option) bool {
+	t.Helper()
+	_, err := Load[T](t, doc, typ, append([]config.Option{config.WithValidation()}, opts...)...)
+	var verr *config.ValidationError
+	if !errors.As(err, &verr) {
+		return assert.Fail(t, fmt.Sprintf("expected validation error of %s, got: %v", typeName[T](), err))
+	}
+	if target != nil {
+		return assert.ErrorIs(t, verr, target)
+	}
+	return true
+}
+
+func typeName[T any]() string {
+	return reflect.TypeOf((*T)(nil)).Elem().String()
+}
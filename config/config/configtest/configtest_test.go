@@ -0,0 +1,85 @@
+package configtest
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jianghushinian/gokit/config/config"
+)
+
+type Config struct {
+	Username string
+	Password string
+	Server   struct {
+		Endpoint string
+		Port     int
+	}
+}
+
+var errPort = errors.New("server.port is required")
+
+func (c *Config) Validate() error {
+	if c.Server.Port == 0 {
+		return errPort
+	}
+	return nil
+}
+
+var expCfg = func() Config {
+	cfg := Config{Username: "user", Password: "pass"}
+	cfg.Server.Endpoint = "https://jianghushinian.cn/"
+	cfg.Server.Port = 8080
+	return cfg
+}()
+
+func TestWriteFile(t *testing.T) {
+	t.Parallel()
+	for _, typ := range []config.FileType{config.FileTypeYAML, config.FileTypeJSON, config.FileTypeTOML, config.FileTypeDir} {
+		typ := typ
+		t.Run(typ.String(), func(t *testing.T) {
+			t.Parallel()
+			filename := WriteFile(t, expCfg, typ, config.WithNaming(config.NamingSnakeCase))
+
+			cfg, err := config.Load[Config](
+				config.WithFile(filename),
+				config.WithFileType(typ),
+				config.WithNaming(config.NamingSnakeCase),
+			)
+			assert.NoError(t, err)
+			assert.Equal(t, expCfg, *cfg)
+		})
+	}
+}
+
+func TestEnv(t *testing.T) {
+	t.Parallel()
+	doc := "username: user\npassword: pass\nserver:\n  endpoint: https://jianghushinian.cn/\n  port: 80\n"
+	for _, port := range []string{"8080", "9090"} {
+		port := port
+		t.Run(port, func(t *testing.T) {
+			t.Parallel()
+			cfg, err := Load[Config](t, doc, config.FileTypeYAML,
+				config.WithEnvPrefix("APP"),
+				Env(map[string]string{"APP_SERVER__PORT": port}),
+			)
+			assert.NoError(t, err)
+			assert.Equal(t, port, strconv.Itoa(cfg.Server.Port))
+		})
+	}
+}
+
+func TestAssertLoads(t *testing.T) {
+	t.Parallel()
+	AssertLoads(t, `{"username": "user", "password": "pass", "server": {"endpoint": "https://jianghushinian.cn/", "port": 8080}}`,
+		config.FileTypeJSON, expCfg)
+}
+
+func TestAssertLoadError(t *testing.T) {
+	t.Parallel()
+	AssertLoadError[Config](t, "server:\n  endpoint: https://a/\n  endpoint: https://b/\n", config.FileTypeYAML, nil)
+	AssertValidationError[Config](t, "username: user\n", config.FileTypeYAML, errPort)
+	AssertValidationError[Config](t, "username: user\n", config.FileTypeYAML, nil)
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Convert 将配置内容从 from 格式转换为 to 格式
+func Convert(data []byte, from, to FileType) ([]byte, error) {
+	tree, err := unmarshalTree(data, from)
+	if err != nil {
+		return nil, err
+	}
+	return marshalTree(plainTree(tree), to)
+}
+
+// toTree 将配置（结构体或映射）转换为通用的配置树，键遵循命名风格
+func toTree(cfg interface{}, o options) (interface{}, error) {
+	data, err := marshal(cfg, FileTypeYAML, o)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := unmarshalTree(data, FileTypeYAML)
+	if err != nil {
+		return nil, err
+	}
+	return plainTree(tree), nil
+}
+
+// plainTree 统一配置树中各格式解码结果的差异，便于格式转换与比较
+// 整数统一为 int64，映射的键统一为字符串
+func plainTree(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = plainTree(e)
+		}
+		return v
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[fmt.Sprint(k)] = plainTree(e)
+		}
+		return m
+	case []interface{}:
+		for i, e := range v {
+			v[i] = plainTree(e)
+		}
+		return v
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	case int:
+		return int64(v)
+	}
+	return v
+}
+
+// lookupPath 按照以 "." 分隔的键路径查找配置树中的值
+func lookupPath(tree interface{}, path []string) (interface{}, bool) {
+	v := tree
+	for _, k := range path {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if v, ok = m[k]; !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
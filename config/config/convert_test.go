@@ -0,0 +1,79 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvert(t *testing.T) {
+	files := map[FileType]string{
+		FileTypeYAML: "testdata/config.yaml",
+		FileTypeJSON: "testdata/config.json",
+		FileTypeTOML: "testdata/config.toml",
+	}
+	for from, filename := range files {
+		data, err := os.ReadFile(filename)
+		assert.NoError(t, err)
+		for to := range files {
+			t.Run(from.String()+" to "+to.String(), func(t *testing.T) {
+				out, err := Convert(data, from, to)
+				assert.NoError(t, err)
+
+				tree, err := unmarshalTree(out, to)
+				assert.NoError(t, err)
+				assert.Equal(t, map[string]interface{}{
+					"username": "user",
+					"password": "pass",
+					"server": map[string]interface{}{
+						"endpoint": "https://jianghushinian.cn/",
+					},
+				}, plainTree(tree))
+			})
+		}
+	}
+}
+
+func TestFileTypeOf(t *testing.T) {
+	for filename, want := range map[string]FileType{
+		"config.yaml": FileTypeYAML,
+		"config.yml":  FileTypeYAML,
+		"config.json": FileTypeJSON,
+		"config.toml": FileTypeTOML,
+		"testdata":    FileTypeDir,
+	} {
+		typ, err := FileTypeOf(filename)
+		assert.NoError(t, err)
+		assert.Equal(t, want, typ)
+	}
+
+	_, err := FileTypeOf("config.ini")
+	assert.EqualError(t, err, "unsupported file type: ini")
+
+	// 文件类型常量为无类型常量，可以作为 int 使用
+	var yamlType, jsonType int = FileTypeYAML, FileTypeJSON
+	assert.Equal(t, 0, yamlType)
+	assert.Equal(t, 1, jsonType)
+	assert.Equal(t, "json", FileType(jsonType).String())
+}
+
+func TestLoadConfigWithOverrides(t *testing.T) {
+	t.Setenv("APP_SERVER__ENDPOINT", "https://env.jianghushinian.cn/")
+
+	var cfg NamingConfig
+	err := LoadYAMLConfig("testdata/config.yaml", &cfg,
+		WithNaming(NamingSnakeCase),
+		WithEnvPrefix("APP"),
+		WithOverrides(map[string]string{
+			"max_idle_conn":       "5",
+			"server.endpoint_url": "https://override.jianghushinian.cn/",
+		}),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, cfg.MaxIdleConn)
+	assert.Equal(t, "https://override.jianghushinian.cn/", cfg.Server.EndpointURL)
+
+	err = LoadYAMLConfig("testdata/config.yaml", &cfg, WithOverrides(map[string]string{"server.unknown": "x"}))
+	assert.EqualError(t, err, `override "server.unknown": unknown config key "unknown"`)
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Change 两份配置之间的一处差异
+type Change struct {
+	Op   string      `json:"op"`   // add、remove 或 replace
+	Path string      `json:"path"` // 以 "." 分隔的键路径
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+
+	keys []string
+}
+
+func (c Change) String() string {
+	switch c.Op {
+	case "add":
+		return fmt.Sprintf("+ %s: %v", c.Path, c.New)
+	case "remove":
+		return fmt.Sprintf("- %s: %v", c.Path, c.Old)
+	}
+	return fmt.Sprintf("~ %s: %v -> %v", c.Path, c.Old, c.New)
+}
+
+// Diff 比较两份配置（结构体或映射）的差异，敏感配置项的值会被隐藏
+func Diff(old, new interface{}, opts ...Option) ([]Change, error) {
+	return diff(old, new, newOptions(opts))
+}
+
+func diff(old, new interface{}, o options) ([]Change, error) {
+	var trees, masked [2]interface{}
+	for i, cfg := range []interface{}{old, new} {
+		var err error
+		if trees[i], err = toTree(cfg, o); err != nil {
+			return nil, err
+		}
+		if masked[i], err = maskedTree(cfg, o); err != nil {
+			return nil, err
+		}
+	}
+
+	changes := diffTree(nil, trees[0], trees[1], nil)
+	for i := range changes {
+		c := &changes[i]
+		if c.Old != nil {
+			c.Old, _ = lookupPath(masked[0], c.keys)
+		}
+		if c.New != nil {
+			c.New, _ = lookupPath(masked[1], c.keys)
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
+func diffTree(path []string, old, new interface{}, changes []Change) []Change {
+	om, ok1 := old.(map[string]interface{})
+	nm, ok2 := new.(map[string]interface{})
+	if ok1 && ok2 {
+		for k, ov := range om {
+			keys := append(append([]string{}, path...), k)
+			if nv, ok := nm[k]; ok {
+				changes = diffTree(keys, ov, nv, changes)
+			} else {
+				changes = append(changes, Change{Op: "remove", Path: strings.Join(keys, "."), Old: ov, keys: keys})
+			}
+		}
+		for k, nv := range nm {
+			if _, ok := om[k]; !ok {
+				keys := append(append([]string{}, path...), k)
+				changes = append(changes, Change{Op: "add", Path: strings.Join(keys, "."), New: nv, keys: keys})
+			}
+		}
+		return changes
+	}
+	if !reflect.DeepEqual(old, new) {
+		changes = append(changes, Change{Op: "replace", Path: strings.Join(path, "."), Old: old, New: new, keys: path})
+	}
+	return changes
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	newCfg := secretCfg
+	newCfg.Username = "admin"
+	newCfg.Password = "new-pass"
+	newCfg.Server.APIToken = ""
+
+	changes, err := Diff(&secretCfg, &newCfg)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"~ password: ****** -> ******",
+		"~ server.api_token: ****** -> ",
+		"~ username: user -> admin",
+	}, changeStrings(changes))
+
+	old := map[string]interface{}{"a": 1, "b": map[string]interface{}{"c": "x"}}
+	cur := map[string]interface{}{"b": map[string]interface{}{"c": "y", "d": true}}
+	changes, err = Diff(old, cur)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"- a: 1",
+		"~ b.c: x -> y",
+		"+ b.d: true",
+	}, changeStrings(changes))
+	assert.Equal(t, "remove", changes[0].Op)
+	assert.Equal(t, int64(1), changes[0].Old)
+}
+
+func changeStrings(changes []Change) []string {
+	s := make([]string, 0, len(changes))
+	for _, c := range changes {
+		s = append(s, c.String())
+	}
+	return s
+}
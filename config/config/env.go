@@ -0,0 +1,80 @@
+package config
+
+import (
+	"encoding"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// applyEnv 使用环境变量覆盖配置树中的值
+// 环境变量名由前缀和配置键组成，嵌套的键使用 "__" 分隔，如 APP_SERVER__ENDPOINT_URL
+func applyEnv(m map[string]interface{}, t reflect.Type, typ FileType, naming Naming, prefix string, lookup func(string) (string, bool)) (changed bool) {
+	return envApplier{typ: typ, naming: naming, lookup: lookup, visiting: make(map[reflect.Type]bool)}.apply(m, t, prefix)
+}
+
+type envApplier struct {
+	typ    FileType
+	naming Naming
+	lookup func(string) (string, bool)
+	// visiting 正在展开的结构体类型，配置树中没有对应的值时不再重复展开，避免自引用的类型无限递归
+	visiting map[reflect.Type]bool
+}
+
+func (a envApplier) apply(m map[string]interface{}, t reflect.Type, prefix string) (changed bool) {
+	t = indirectType(t)
+	a.visiting[t] = true
+	defer delete(a.visiting, t)
+	for _, f := range structFields(t, a.typ, a.naming) {
+		if f.inline {
+			continue
+		}
+		name := prefix + envName(f.key)
+		key, ok := lookupKey(m, f.native, f.fold)
+		if !ok {
+			key = f.native
+		}
+
+		ft := indirectType(f.typ)
+		if ft.Kind() == reflect.Struct && !reflect.PtrTo(ft).Implements(textUnmarshalerType) {
+			sub, _ := m[key].(map[string]interface{})
+			if sub == nil {
+				if a.visiting[ft] {
+					continue
+				}
+				sub = make(map[string]interface{})
+			}
+			if a.apply(sub, ft, name+"__") {
+				m[key] = sub
+				changed = true
+			}
+			continue
+		}
+
+		if v, ok := a.lookup(name); ok {
+			m[key] = parseValue(v, ft)
+			changed = true
+		}
+	}
+	return changed
+}
+
+func envName(key string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(key))
+}
+
+// parseValue 将字符串（如环境变量的值）转换为字段类型对应的值，非字符串类型按照 YAML 标量解析
+// t 为 nil 时同样按照 YAML 标量解析
+func parseValue(v string, t reflect.Type) interface{} {
+	if t != nil && (t.Kind() == reflect.String || reflect.PtrTo(t).Implements(textUnmarshalerType)) {
+		return v
+	}
+	var val interface{}
+	if err := yaml.Unmarshal([]byte(v), &val); err != nil || val == nil {
+		return v
+	}
+	return val
+}
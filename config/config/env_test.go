@@ -0,0 +1,72 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfigWithEnvPrefix(t *testing.T) {
+	t.Setenv("APP_USERNAME", "env-user")
+	t.Setenv("APP_SERVER__ENDPOINT", "https://env.jianghushinian.cn/")
+
+	for _, tt := range []struct {
+		filename string
+		typ      FileType
+	}{
+		{filename: "testdata/config.yaml", typ: FileTypeYAML},
+		{filename: "testdata/config.json", typ: FileTypeJSON},
+	} {
+		var cfg Config
+		err := LoadConfig(tt.filename, &cfg, tt.typ, WithEnvPrefix("APP"))
+		assert.NoError(t, err)
+		assert.Equal(t, "env-user", cfg.Username)
+		assert.Equal(t, "pass", cfg.Password)
+		assert.Equal(t, "https://env.jianghushinian.cn/", cfg.Server.Endpoint)
+	}
+}
+
+func TestLoadConfigWithEnvPrefixAndNaming(t *testing.T) {
+	t.Setenv("APP_MAX_IDLE_CONN", "20")
+	t.Setenv("APP_SERVER__ENDPOINT_URL", "https://env.jianghushinian.cn/")
+	t.Setenv("APP_SERVER__PORT", "9090")
+
+	var cfg NamingConfig
+	err := LoadYAMLConfig("testdata/config.yaml", &cfg, WithEnvPrefix("APP"), WithNaming(NamingSnakeCase))
+	assert.NoError(t, err)
+	assert.Equal(t, "user", cfg.Username)
+	assert.Equal(t, 20, cfg.MaxIdleConn)
+	assert.Equal(t, "https://env.jianghushinian.cn/", cfg.Server.EndpointURL)
+	assert.Equal(t, 9090, cfg.Server.HTTPPort)
+}
+
+// Route 自引用的配置类型
+type Route struct {
+	Path     string
+	Children []Route
+	Fallback *Route
+}
+
+func TestLoadConfigWithEnvPrefixRecursiveType(t *testing.T) {
+	t.Setenv("APP_PATH", "/env")
+	t.Setenv("APP_FALLBACK__PATH", "/env-fallback")
+	t.Setenv("APP_FALLBACK__FALLBACK__PATH", "/env-nested")
+
+	filename := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(filename, []byte("path: /\nchildren:\n  - path: /users\n"), 0644))
+
+	var cfg Route
+	require.NoError(t, LoadYAMLConfig(filename, &cfg, WithEnvPrefix("APP")))
+	assert.Equal(t, "/env", cfg.Path)
+	assert.Equal(t, []Route{{Path: "/users"}}, cfg.Children)
+	// 配置文件中没有值的自引用字段不会展开，无法通过环境变量设置
+	assert.Nil(t, cfg.Fallback)
+
+	require.NoError(t, os.WriteFile(filename, []byte("fallback:\n  path: /fallback\n"), 0644))
+	cfg = Route{}
+	require.NoError(t, LoadYAMLConfig(filename, &cfg, WithEnvPrefix("APP")))
+	assert.Equal(t, &Route{Path: "/env-fallback"}, cfg.Fallback)
+}
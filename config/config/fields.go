@@ -0,0 +1,195 @@
+package config
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// field 描述结构体字段与配置文档中键的对应关系
+type field struct {
+	name       string // Go 字段名
+	key        string // 配置文档中的键
+	native     string // 解码器识别的键
+	fold       bool   // 键是否大小写不敏感（JSON、TOML）
+	index      []int
+	typ        reflect.Type
+	aliases    []string
+	deprecated string
+	secret     bool // 是否为敏感字段
+	inline     bool // 是否为内联的映射字段
+}
+
+// structFields 解析结构体字段，内嵌字段会被展开
+// 未通过标签显式指定键名的字段，其在配置文档中的键由命名风格 naming 决定
+func structFields(t reflect.Type, typ FileType, naming Naming) []field {
+	var fields []field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() && !sf.Anonymous {
+			continue
+		}
+
+		name, explicit, inline, skip := parseTag(sf, typ)
+		if skip {
+			continue
+		}
+		if inline {
+			ft := indirectType(sf.Type)
+			if ft.Kind() == reflect.Struct {
+				for _, f := range structFields(ft, typ, naming) {
+					f.index = append([]int{i}, f.index...)
+					fields = append(fields, f)
+				}
+				continue
+			}
+			// 内联的映射字段接收其他所有的键
+			fields = append(fields, field{name: sf.Name, inline: true, index: []int{i}, typ: sf.Type})
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+
+		f := field{
+			name:       sf.Name,
+			key:        name,
+			native:     name,
+			fold:       typ != FileTypeYAML && naming == NamingDefault,
+			index:      []int{i},
+			typ:        sf.Type,
+			deprecated: sf.Tag.Get("deprecated"),
+			secret:     sf.Tag.Get("secret") == "true",
+		}
+		if !explicit && naming != NamingDefault {
+			f.key = naming.key(sf.Name)
+		}
+		if aliases := sf.Tag.Get("aliases"); aliases != "" {
+			for _, a := range strings.Split(aliases, ",") {
+				if a = strings.TrimSpace(a); a != "" {
+					f.aliases = append(f.aliases, a)
+				}
+			}
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// parseTag 按照对应格式解码器的规则解析字段的键名，explicit 表示键名由标签显式指定
+func parseTag(sf reflect.StructField, typ FileType) (name string, explicit, inline, skip bool) {
+	switch typ {
+	case FileTypeJSON, FileTypeTOML:
+		tag := sf.Tag.Get(typ.String())
+		if tag == "-" {
+			return "", false, false, true
+		}
+		name, _, _ = strings.Cut(tag, ",")
+		if name == "" && sf.Anonymous {
+			return "", false, true, false
+		}
+		if name != "" {
+			return name, true, false, false
+		}
+		name = sf.Name
+	default:
+		tag := sf.Tag.Get("yaml")
+		if tag == "-" {
+			return "", false, false, true
+		}
+		var flags string
+		name, flags, _ = strings.Cut(tag, ",")
+		for _, flag := range strings.Split(flags, ",") {
+			if flag == "inline" {
+				return "", false, true, false
+			}
+		}
+		if name != "" {
+			return name, true, false, false
+		}
+		name = strings.ToLower(sf.Name)
+	}
+	return name, false, false, false
+}
+
+// lookupKey 在配置树中查找键，返回文档中实际使用的键
+func lookupKey(m map[string]interface{}, key string, fold bool) (string, bool) {
+	if _, ok := m[key]; ok {
+		return key, true
+	}
+	if fold {
+		for k := range m {
+			if strings.EqualFold(k, key) {
+				return k, true
+			}
+		}
+	}
+	return "", false
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// walkTree 按照结构体类型遍历配置树，对每一层结构体对应的映射调用 fn
+// fn 可以修改映射，字段的值会依次按照解码器识别的键、配置文档中的键查找
+func walkTree(v interface{}, t reflect.Type, typ FileType, naming Naming, path string, fn func(m map[string]interface{}, fields []field, path string) error) error {
+	t = indirectType(t)
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		fields := structFields(t, typ, naming)
+		if err := fn(m, fields, path); err != nil {
+			return err
+		}
+		for _, f := range fields {
+			k, ok := lookupKey(m, f.native, f.fold)
+			if !ok {
+				if k, ok = lookupKey(m, f.key, f.fold); !ok {
+					continue
+				}
+			}
+			seg := f.key
+			if f.fold {
+				seg = k
+			}
+			if err := walkTree(m[k], f.typ, typ, naming, joinPath(path, seg), fn); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		s, ok := v.([]interface{})
+		if !ok {
+			return nil
+		}
+		for i, e := range s {
+			if err := walkTree(e, t.Elem(), typ, naming, joinPath(path, strconv.Itoa(i)), fn); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		for k, e := range m {
+			if err := walkTree(e, t.Elem(), typ, naming, joinPath(path, k), fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
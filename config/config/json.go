@@ -5,34 +5,34 @@ import (
 	"os"
 )
 
-func LoadJSONConfig(filename string, cfg interface{}) error {
-	return LoadConfig(filename, cfg, FileTypeJSON)
+func LoadJSONConfig(filename string, cfg interface{}, opts ...Option) error {
+	return LoadConfig(filename, cfg, FileTypeJSON, opts...)
 }
 
-func LoadJSONConfigFromFlag(cfg interface{}) error {
+func LoadJSONConfigFromFlag(cfg interface{}, opts ...Option) error {
 	if !flag.Parsed() {
 		flag.Parse()
 	}
-	return LoadJSONConfig(*cfgPath, cfg)
+	return LoadJSONConfig(*cfgPath, cfg, opts...)
 }
 
-func DumpJSONConfig(filename string, cfg interface{}) error {
-	return DumpConfig(filename, cfg, FileTypeJSON)
+func DumpJSONConfig(filename string, cfg interface{}, opts ...Option) error {
+	return DumpConfig(filename, cfg, FileTypeJSON, opts...)
 }
 
-func DumpJSONConfigFromFlag(cfg interface{}) error {
+func DumpJSONConfigFromFlag(cfg interface{}, opts ...Option) error {
 	if !flag.Parsed() {
 		flag.Parse()
 	}
-	return DumpJSONConfig(*cfgPath, cfg)
+	return DumpJSONConfig(*cfgPath, cfg, opts...)
 }
 
-func LoadOrDumpJSONConfigFromFlag(cfg interface{}) error {
+func LoadOrDumpJSONConfigFromFlag(cfg interface{}, opts ...Option) error {
 	if *dump {
-		if err := DumpJSONConfigFromFlag(cfg); err != nil {
+		if err := DumpJSONConfigFromFlag(cfg, opts...); err != nil {
 			return err
 		}
 		os.Exit(0)
 	}
-	return LoadJSONConfigFromFlag(cfg)
+	return LoadJSONConfigFromFlag(cfg, opts...)
 }
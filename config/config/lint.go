@@ -0,0 +1,163 @@
+package config
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// LintReport 配置文档检查结果
+type LintReport struct {
+	File        string   `json:"file"`
+	UnusedKeys  []string `json:"unusedKeys"`         // 没有对应字段的键
+	UnsetFields []string `json:"unsetFields"`        // 配置文档没有设置，且没有默认值的字段
+	Warnings    []string `json:"warnings,omitempty"` // 使用了已废弃的键等告警
+}
+
+// OK 配置文档是否没有问题
+func (r *LintReport) OK() bool {
+	return len(r.UnusedKeys) == 0 && len(r.UnsetFields) == 0
+}
+
+// Lint 检查配置文档，列出没有对应字段的键，以及配置文档没有设置且没有默认值的字段
+// cfg 为配置结构体指针，加载前已经设置的非零值视为字段的默认值，cfg 不会被修改
+// 只检查配置文档本身，不包括环境变量以及覆盖值
+func Lint(filename string, cfg interface{}, typ FileType, opts ...Option) (*LintReport, error) {
+	o := newOptions(opts)
+	data, tree, err := readSource(filename, typ)
+	if err != nil {
+		return nil, err
+	}
+	if typ == FileTypeDir {
+		typ = FileTypeYAML
+		expandDirKeys(tree, reflect.TypeOf(cfg), typ, o.naming)
+	} else if tree, err = unmarshalTree(data, typ); err != nil {
+		return nil, err
+	}
+
+	r := &LintReport{File: filename, UnusedKeys: []string{}, UnsetFields: []string{}}
+	m, _ := tree.(map[string]interface{})
+	l := linter{typ: typ, naming: o.naming, report: r, visiting: make(map[reflect.Type]bool)}
+	l.lint(m, reflect.ValueOf(cfg), "")
+	sort.Strings(r.UnusedKeys)
+	sort.Strings(r.UnsetFields)
+	return r, nil
+}
+
+type linter struct {
+	typ    FileType
+	naming Naming
+	report *LintReport
+	// visiting 正在检查的结构体类型，没有设置的自引用字段不再展开，避免无限递归
+	visiting map[reflect.Type]bool
+}
+
+// lint 检查一层结构体对应的映射，def 为字段的默认值
+func (l *linter) lint(m map[string]interface{}, def reflect.Value, path string) {
+	for def.Kind() == reflect.Ptr || def.Kind() == reflect.Interface {
+		if def.IsNil() {
+			def = reflect.Zero(indirectType(def.Type()))
+			break
+		}
+		def = def.Elem()
+	}
+	if def.Kind() != reflect.Struct {
+		return
+	}
+	l.visiting[def.Type()] = true
+	defer delete(l.visiting, def.Type())
+
+	fields := structFields(def.Type(), l.typ, l.naming)
+	used := make(map[string]struct{}, len(m))
+	inline := false
+	for _, f := range fields {
+		if f.inline {
+			inline = true
+			continue
+		}
+		key, _, warning, err := resolveAlias(m, f, path)
+		switch {
+		case err != nil:
+			l.report.Warnings = append(l.report.Warnings, err.Error())
+		case warning != "":
+			l.report.Warnings = append(l.report.Warnings, warning)
+		}
+		if key == "" {
+			l.unset(f, fieldByIndex(def, f.index), joinPath(path, f.key))
+			continue
+		}
+		used[key] = struct{}{}
+		l.value(m[key], f.typ, fieldByIndex(def, f.index), joinPath(path, key))
+	}
+	if inline {
+		return
+	}
+	for k := range m {
+		if _, ok := used[k]; !ok {
+			l.report.UnusedKeys = append(l.report.UnusedKeys, joinPath(path, k))
+		}
+	}
+}
+
+// value 检查字段的值，递归检查其中的结构体
+func (l *linter) value(v interface{}, t reflect.Type, def reflect.Value, path string) {
+	t = indirectType(t)
+	switch {
+	case t.Kind() == reflect.Struct && !reflect.PtrTo(t).Implements(textUnmarshalerType):
+		if m, ok := v.(map[string]interface{}); ok {
+			l.lint(m, def, path)
+		}
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		if s, ok := v.([]interface{}); ok {
+			for i, e := range s {
+				l.value(e, t.Elem(), reflect.Zero(t.Elem()), joinPath(path, strconv.Itoa(i)))
+			}
+		}
+	case t.Kind() == reflect.Map:
+		if m, ok := v.(map[string]interface{}); ok {
+			for k, e := range m {
+				l.value(e, t.Elem(), reflect.Zero(t.Elem()), joinPath(path, k))
+			}
+		}
+	}
+}
+
+// unset 记录配置文档没有设置的字段，结构体字段会展开记录其中没有默认值的字段，
+// 自引用的结构体字段不会展开，没有默认值时记录字段本身
+func (l *linter) unset(f field, def reflect.Value, path string) {
+	t := indirectType(f.typ)
+	if t.Kind() == reflect.Struct && !reflect.PtrTo(t).Implements(textUnmarshalerType) && !l.visiting[t] {
+		l.visiting[t] = true
+		defer delete(l.visiting, t)
+		for def.Kind() == reflect.Ptr {
+			if def.IsNil() {
+				def = reflect.Zero(t)
+				break
+			}
+			def = def.Elem()
+		}
+		for _, sub := range structFields(t, l.typ, l.naming) {
+			if !sub.inline {
+				l.unset(sub, fieldByIndex(def, sub.index), joinPath(path, sub.key))
+			}
+		}
+		return
+	}
+	if def.IsZero() {
+		l.report.UnsetFields = append(l.report.UnsetFields, path)
+	}
+}
+
+// fieldByIndex 与 reflect.Value.FieldByIndex 相同，内嵌的结构体指针为 nil 时返回零值
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Zero(v.Type().Elem().FieldByIndex(index[i:]).Type)
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
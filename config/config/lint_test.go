@@ -0,0 +1,108 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type LintConfig struct {
+	Username string
+	Password string
+	Level    string
+	Server   struct {
+		URL     string `yaml:"url" json:"url" aliases:"endpoint" deprecated:"use server.url instead"`
+		Timeout int    `yaml:"timeout" json:"timeout"`
+	}
+	Nodes  []struct{ Host string }
+	Labels map[string]string
+	Extra  map[string]interface{} `yaml:",inline" json:"-"`
+}
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		typ  FileType
+		cfg  *LintConfig
+		want LintReport
+	}{
+		{
+			name: "yaml",
+			doc: `username: user
+pasword: pass
+server:
+  endpoint: https://jianghushinian.cn/
+  timout: 3
+nodes:
+  - host: a
+    port: 80
+labels:
+  app: demo
+`,
+			typ: FileTypeYAML,
+			cfg: &LintConfig{},
+			want: LintReport{
+				UnusedKeys:  []string{"nodes.0.port", "server.timout"},
+				UnsetFields: []string{"level", "password", "server.timeout"},
+				Warnings:    []string{`config key "server.endpoint" is deprecated: use server.url instead`},
+			},
+		},
+		{
+			name: "json with defaults",
+			doc:  `{"USERNAME": "user", "password": "pass", "server": {"url": "https://jianghushinian.cn/"}, "unknown": 1, "nodes": [], "labels": {}}`,
+			typ:  FileTypeJSON,
+			cfg:  &LintConfig{Level: "info"},
+			want: LintReport{
+				UnusedKeys:  []string{"unknown"},
+				UnsetFields: []string{"server.timeout"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "config."+tt.typ.String())
+			_ = os.WriteFile(filename, []byte(tt.doc), 0644)
+
+			r, err := Lint(filename, tt.cfg, tt.typ)
+			assert.NoError(t, err)
+			tt.want.File = filename
+			assert.Equal(t, &tt.want, r)
+			assert.False(t, r.OK())
+		})
+	}
+}
+
+func TestLintWithNaming(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "config.yaml")
+	_ = os.WriteFile(filename, []byte("username: user\nmax_idle_conn: 10\nMaxIdleConn: 20\nserver:\n  endpoint_url: u\n  port: 80\n"), 0644)
+
+	r, err := Lint(filename, &NamingConfig{}, FileTypeYAML, WithNaming(NamingSnakeCase))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"MaxIdleConn"}, r.UnusedKeys)
+	assert.Empty(t, r.UnsetFields)
+}
+
+func TestLintOK(t *testing.T) {
+	r, err := Lint("testdata/config.yaml", &ValidatedConfig{}, FileTypeYAML)
+	assert.NoError(t, err)
+	assert.True(t, r.OK())
+	assert.Equal(t, LintReport{File: "testdata/config.yaml", UnusedKeys: []string{}, UnsetFields: []string{}}, *r)
+}
+
+func TestLintRecursiveType(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, os.WriteFile(filename, []byte("path: /\nfallback:\n  path: /fallback\n"), 0644))
+
+	r, err := Lint(filename, &Route{}, FileTypeYAML)
+	assert.NoError(t, err)
+	// 没有设置的自引用字段不会展开
+	assert.Equal(t, []string{"children", "fallback.children", "fallback.fallback"}, r.UnsetFields)
+	assert.Empty(t, r.UnusedKeys)
+
+	r, err = Lint(filename, &Route{Children: []Route{{Path: "/users"}}}, FileTypeYAML)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"fallback.children", "fallback.fallback"}, r.UnsetFields)
+}
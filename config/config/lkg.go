@@ -0,0 +1,86 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Status 配置加载状态，可用于健康检查
+type Status struct {
+	Checksum string    `json:"checksum"` // 当前生效配置的 SHA-256 摘要
+	Source   string    `json:"source"`   // 当前生效配置的来源
+	Fallback bool      `json:"fallback"` // 是否使用了 last-known-good 配置
+	LoadedAt time.Time `json:"loadedAt"` // 当前生效配置的加载时间
+	Err      error     `json:"-"`        // 最近一次加载配置失败的原因
+}
+
+// Healthy 最近一次加载配置是否成功
+func (s Status) Healthy() bool {
+	return s.Err == nil && s.Checksum != ""
+}
+
+func (s Status) MarshalJSON() ([]byte, error) {
+	type status Status
+	v := struct {
+		status
+		Error string `json:"error,omitempty"`
+	}{status: status(s)}
+	if s.Err != nil {
+		v.Error = s.Err.Error()
+	}
+	return json.Marshal(v)
+}
+
+// errChecksumMismatch last-known-good 配置内容与保存的摘要不一致
+var errChecksumMismatch = errors.New("checksum mismatch")
+
+// saveLastKnownGood 保存配置内容以及摘要，摘要根据实际保存的内容计算，保存在同名的 .sha256 文件中
+func saveLastKnownGood(filename string, data []byte) error {
+	if err := writeFileAtomic(filename, data); err != nil {
+		return err
+	}
+	return writeFileAtomic(filename+".sha256", []byte(checksum(data)+"\n"))
+}
+
+// loadLastKnownGood 读取配置内容并校验摘要，内容被修改或损坏时返回错误
+func loadLastKnownGood(filename string) (data []byte, sum string, err error) {
+	if data, err = os.ReadFile(filename); err != nil {
+		return nil, "", err
+	}
+	s, err := os.ReadFile(filename + ".sha256")
+	if err != nil {
+		return nil, "", err
+	}
+	sum = strings.TrimSpace(string(s))
+	if actual := checksum(data); actual != sum {
+		return nil, "", fmt.Errorf("%s: %w: expected %s, got %s", filename, errChecksumMismatch, sum, actual)
+	}
+	return data, sum, nil
+}
+
+// writeFileAtomic 先写入临时文件再重命名，避免进程中断时留下不完整的文件
+func writeFileAtomic(filename string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(data); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return err
+	}
+	if err = f.Close(); err != nil {
+		_ = os.Remove(f.Name())
+		return err
+	}
+	if err = os.Rename(f.Name(), filename); err != nil {
+		_ = os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
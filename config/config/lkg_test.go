@@ -0,0 +1,160 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ValidatedConfig struct {
+	Username string
+	Password string
+	Server   struct {
+		Endpoint string
+	}
+}
+
+var errEmptyEndpoint = errors.New("server.endpoint is required")
+
+func (c *ValidatedConfig) Validate() error {
+	if c.Server.Endpoint == "" {
+		return errEmptyEndpoint
+	}
+	return nil
+}
+
+func TestLoaderLastKnownGood(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "config.yaml")
+	lkg := filepath.Join(dir, "config.lkg.yaml")
+	data, _ := os.ReadFile("testdata/config.yaml")
+	require.NoError(t, os.WriteFile(filename, data, 0644))
+	sum := sha256.Sum256(data)
+
+	// 首次加载成功，保存 last-known-good 配置
+	l := NewLoader(filename, FileTypeYAML, WithLastKnownGood(lkg))
+	var cfg ValidatedConfig
+	require.NoError(t, l.Load(&cfg))
+	status := l.Status()
+	assert.True(t, status.Healthy())
+	assert.False(t, status.Fallback)
+	assert.Equal(t, hex.EncodeToString(sum[:]), status.Checksum)
+	saved, err := os.ReadFile(lkg)
+	assert.NoError(t, err)
+	assert.Equal(t, data, saved)
+
+	tests := []struct {
+		name    string
+		content string
+		target  error
+	}{
+		{name: "parse error", content: "username: [user"},
+		{name: "validation error", content: "username: user\n", target: errEmptyEndpoint},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, os.WriteFile(filename, []byte(tt.content), 0644))
+
+			// 启动时主配置加载失败，使用 last-known-good 配置
+			l := NewLoader(filename, FileTypeYAML, WithLastKnownGood(lkg))
+			var cfg ValidatedConfig
+			assert.NoError(t, l.Load(&cfg))
+			assert.Equal(t, "https://jianghushinian.cn/", cfg.Server.Endpoint)
+
+			status := l.Status()
+			assert.False(t, status.Healthy())
+			assert.True(t, status.Fallback)
+			assert.Equal(t, lkg, status.Source)
+			assert.Equal(t, hex.EncodeToString(sum[:]), status.Checksum)
+			assert.Error(t, status.Err)
+			if tt.target != nil {
+				assert.ErrorIs(t, status.Err, tt.target)
+			}
+			assert.Len(t, l.Warnings(), 1)
+
+			data, err := json.Marshal(status)
+			assert.NoError(t, err)
+			assert.Contains(t, string(data), `"fallback":true`)
+			assert.Contains(t, string(data), `"error":`)
+		})
+	}
+}
+
+func TestLoaderReloadFailureKeepsActiveConfig(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "config.yaml")
+	data, _ := os.ReadFile("testdata/config.yaml")
+	require.NoError(t, os.WriteFile(filename, data, 0644))
+
+	l := NewLoader(filename, FileTypeYAML, WithLastKnownGood(filepath.Join(dir, "lkg.yaml")))
+	var cfg ValidatedConfig
+	require.NoError(t, l.Load(&cfg))
+	checksum := l.Status().Checksum
+
+	require.NoError(t, os.WriteFile(filename, []byte("username: other\n"), 0644))
+	var next ValidatedConfig
+	err := l.Load(&next)
+	var verr *ValidationError
+	assert.ErrorAs(t, err, &verr)
+	assert.ErrorIs(t, err, errEmptyEndpoint)
+	assert.Equal(t, ValidatedConfig{}, next)
+
+	status := l.Status()
+	assert.Equal(t, checksum, status.Checksum)
+	assert.False(t, status.Fallback)
+	assert.Equal(t, err, status.Err)
+}
+
+func TestLoaderLastKnownGoodChecksum(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "config.yaml")
+	lkg := filepath.Join(dir, "config.lkg.yaml")
+	data, _ := os.ReadFile("testdata/config.yaml")
+	require.NoError(t, os.WriteFile(filename, data, 0644))
+	var cfg ValidatedConfig
+	require.NoError(t, NewLoader(filename, FileTypeYAML, WithLastKnownGood(lkg)).Load(&cfg))
+
+	// last-known-good 配置被修改后不会使用
+	require.NoError(t, os.WriteFile(lkg, append(data, "username: admin\n"...), 0644))
+	require.NoError(t, os.WriteFile(filename, []byte("username: [user"), 0644))
+	l := NewLoader(filename, FileTypeYAML, WithLastKnownGood(lkg))
+	var next ValidatedConfig
+	assert.Error(t, l.Load(&next))
+	assert.Equal(t, ValidatedConfig{}, next)
+	assert.False(t, l.Status().Fallback)
+	require.Len(t, l.Warnings(), 1)
+	assert.Contains(t, l.Warnings()[0], errChecksumMismatch.Error())
+}
+
+func TestLoaderLastKnownGoodDir(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "config")
+	lkg := filepath.Join(dir, "config.lkg.yaml")
+	require.NoError(t, os.Mkdir(src, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "username"), []byte("user"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "server__endpoint"), []byte("https://jianghushinian.cn/"), 0644))
+
+	var cfg ValidatedConfig
+	require.NoError(t, NewLoader(src, FileTypeDir, WithLastKnownGood(lkg)).Load(&cfg))
+	// 摘要根据实际保存的 YAML 内容计算
+	saved, err := os.ReadFile(lkg)
+	require.NoError(t, err)
+	sum, err := os.ReadFile(lkg + ".sha256")
+	require.NoError(t, err)
+	assert.Equal(t, checksum(saved)+"\n", string(sum))
+
+	require.NoError(t, os.Remove(filepath.Join(src, "server__endpoint")))
+	l := NewLoader(src, FileTypeDir, WithLastKnownGood(lkg))
+	var next ValidatedConfig
+	require.NoError(t, l.Load(&next))
+	assert.Equal(t, cfg, next)
+	assert.True(t, l.Status().Fallback)
+	assert.Equal(t, checksum(saved), l.Status().Checksum)
+}
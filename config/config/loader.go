@@ -0,0 +1,250 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Loader 配置加载器，会记录加载过程中产生的告警以及当前生效配置的状态
+type Loader struct {
+	filename string
+	typ      FileType
+	opts     options
+
+	mu       sync.Mutex
+	warnings []string
+	status   Status
+}
+
+func NewLoader(filename string, typ FileType, opts ...Option) *Loader {
+	return &Loader{
+		filename: filename,
+		typ:      typ,
+		opts:     newOptions(opts),
+	}
+}
+
+// Load 加载配置到 cfg，加载失败时 cfg 保持不变
+func (l *Loader) Load(cfg interface{}) error {
+	l.mu.Lock()
+	err := l.load(cfg)
+	warnings := l.warnings
+	l.mu.Unlock()
+
+	if l.opts.warningHandler != nil {
+		for _, w := range warnings {
+			l.opts.warningHandler(w)
+		}
+	}
+	return err
+}
+
+// Warnings 返回最近一次加载配置时产生的告警
+func (l *Loader) Warnings() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.warnings
+}
+
+// Status 返回当前生效配置的状态
+func (l *Loader) Status() Status {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.status
+}
+
+// Changed 检查配置内容自上次成功加载后是否发生变化
+func (l *Loader) Changed() (bool, error) {
+	data, _, err := readSource(l.filename, l.typ)
+	if err != nil {
+		return false, err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return checksum(data) != l.status.Checksum, nil
+}
+
+// Watch 每隔 interval 检查一次配置内容，内容变化时调用 fn，直到 ctx 结束
+// 由于比较的是配置内容的摘要，因此同样适用于 Kubernetes 通过 ..data 符号链接切换的目录
+// fn 中通常会调用 Load 重新加载配置，加载失败时下次检查仍会再次调用 fn
+func (l *Loader) Watch(ctx context.Context, interval time.Duration, fn func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if changed, err := l.Changed(); err == nil && changed {
+				fn()
+			}
+		}
+	}
+}
+
+func (l *Loader) load(cfg interface{}) error {
+	l.warnings = nil
+
+	data, tree, err := readSource(l.filename, l.typ)
+	if err == nil {
+		var backup []byte
+		if l.opts.lastKnownGood != "" {
+			backup = data
+			if tree != nil {
+				// 目录配置以 YAML 格式保存
+				backup, err = marshalTree(tree, FileTypeYAML)
+			}
+		}
+		if err == nil {
+			err = l.decode(data, tree, cfg)
+		}
+		if err == nil {
+			sum := checksum(data)
+			l.status = Status{Checksum: sum, Source: l.filename, LoadedAt: time.Now()}
+			if backup != nil {
+				if err := saveLastKnownGood(l.opts.lastKnownGood, backup); err != nil {
+					l.warnings = append(l.warnings, fmt.Sprintf("save last known good config: %v", err))
+				}
+			}
+			return nil
+		}
+	}
+
+	l.status.Err = err
+	// 仅在首次加载失败时使用 last-known-good 配置，热加载失败时保留当前生效的配置
+	if l.status.Checksum != "" || l.opts.lastKnownGood == "" {
+		return err
+	}
+	if ferr := l.fallback(cfg); ferr != nil {
+		l.warnings = append(l.warnings, fmt.Sprintf("load last known good config: %v", ferr))
+		return err
+	}
+	l.warnings = append(l.warnings, fmt.Sprintf("load config failed, fall back to last known good config: %v", err))
+	return nil
+}
+
+func (l *Loader) fallback(cfg interface{}) error {
+	data, sum, err := loadLastKnownGood(l.opts.lastKnownGood)
+	if err != nil {
+		return err
+	}
+	var tree interface{}
+	if l.typ == FileTypeDir {
+		if tree, err = unmarshalTree(data, FileTypeYAML); err != nil {
+			return err
+		}
+	}
+	warnings := l.warnings
+	if err = l.decode(data, tree, cfg); err != nil {
+		return err
+	}
+	l.warnings = append(warnings, l.warnings...)
+	l.status.Checksum = sum
+	l.status.Source = l.opts.lastKnownGood
+	l.status.Fallback = true
+	l.status.LoadedAt = time.Now()
+	return nil
+}
+
+// decode 解码并校验配置，先解码到 cfg 的副本，成功后再写回，避免失败时 cfg 被部分修改
+func (l *Loader) decode(data []byte, tree interface{}, cfg interface{}) error {
+	v := reflect.ValueOf(cfg)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return l.unmarshal(data, tree, cfg)
+	}
+	target := reflect.New(v.Elem().Type())
+	target.Elem().Set(v.Elem())
+	if err := l.unmarshal(data, tree, target.Interface()); err != nil {
+		return err
+	}
+	if err := validate(target.Interface(), l.opts); err != nil {
+		return err
+	}
+	v.Elem().Set(target.Elem())
+	return nil
+}
+
+func (l *Loader) unmarshal(data []byte, tree interface{}, cfg interface{}) error {
+	// 目录中读取的配置树按照 YAML 格式解码
+	typ := l.typ
+	if typ == FileTypeDir {
+		typ = FileTypeYAML
+	}
+
+	t := reflect.TypeOf(cfg)
+	isStruct := t != nil && indirectType(t).Kind() == reflect.Struct
+	if !isStruct && tree == nil && len(l.opts.overrides) == 0 {
+		return unmarshal(data, cfg, typ)
+	}
+
+	changed := tree != nil
+	if tree == nil {
+		var err error
+		if tree, err = unmarshalTree(data, typ); err != nil {
+			return err
+		}
+	}
+	if l.typ == FileTypeDir {
+		expandDirKeys(tree, t, typ, l.opts.naming)
+	}
+	if isStruct {
+		normalized, warnings, err := normalize(tree, t, typ, l.opts.naming)
+		if err != nil {
+			return err
+		}
+		changed = changed || normalized
+		l.warnings = warnings
+		if l.typ == FileTypeDir {
+			if err = convertValues(tree, t, typ, l.opts.naming); err != nil {
+				return err
+			}
+		}
+	}
+	if l.opts.envPrefix != "" || len(l.opts.overrides) > 0 {
+		if tree == nil {
+			tree = make(map[string]interface{})
+		}
+		if m, ok := tree.(map[string]interface{}); ok {
+			overlaid, err := l.overlay(m, t, typ)
+			if err != nil {
+				return err
+			}
+			changed = changed || overlaid
+		}
+	}
+	if !changed {
+		return unmarshal(data, cfg, typ)
+	}
+
+	data, err := marshalTree(tree, typ)
+	if err != nil {
+		return err
+	}
+	return unmarshal(data, cfg, typ)
+}
+
+// overlay 使用环境变量以及覆盖值覆盖配置树中的值
+// 非结构体类型的配置无法确定环境变量名，仅支持覆盖值
+func (l *Loader) overlay(m map[string]interface{}, t reflect.Type, typ FileType) (changed bool, err error) {
+	if t == nil || indirectType(t).Kind() != reflect.Struct {
+		for path, v := range l.opts.overrides {
+			setPath(m, strings.Split(path, "."), parseValue(v, nil))
+			changed = true
+		}
+		return changed, nil
+	}
+	if l.opts.envPrefix != "" {
+		changed = applyEnv(m, t, typ, l.opts.naming, l.opts.envPrefix+"_", l.opts.lookupEnv)
+	}
+	if len(l.opts.overrides) > 0 {
+		if err = applyOverrides(m, t, typ, l.opts.naming, l.opts.overrides); err != nil {
+			return false, err
+		}
+		changed = true
+	}
+	return changed, nil
+}
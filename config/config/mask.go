@@ -0,0 +1,94 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+// MaskedValue 敏感配置项被隐藏后的值
+const MaskedValue = "******"
+
+// secretKeywords 键名包含这些关键字（忽略大小写、"_" 以及 "-"）的配置项被视为敏感配置项
+var secretKeywords = []string{
+	"password", "passwd", "secret", "token", "credential", "apikey", "privatekey", "accesskey",
+}
+
+func isSecretKey(key string) bool {
+	key = strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(key))
+	for _, kw := range secretKeywords {
+		if strings.Contains(key, kw) {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalMasked 按照 typ 格式编码配置并隐藏敏感配置项的值，可用于输出或展示当前生效的配置
+// 敏感配置项为声明了 `secret:"true"` 标签的字段，或者键名包含 password、secret、token 等关键字的配置项
+func MarshalMasked(cfg interface{}, typ FileType, opts ...Option) ([]byte, error) {
+	return marshalMasked(cfg, typ, newOptions(opts))
+}
+
+func marshalMasked(cfg interface{}, typ FileType, o options) ([]byte, error) {
+	data, err := marshal(cfg, typ, o)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := unmarshalTree(data, typ)
+	if err != nil {
+		return nil, err
+	}
+	maskTree(tree, reflect.TypeOf(cfg), typ, o.naming)
+	return marshalTree(tree, typ)
+}
+
+// maskedTree 将配置转换为配置树并隐藏敏感配置项的值
+func maskedTree(cfg interface{}, o options) (interface{}, error) {
+	tree, err := toTree(cfg, o)
+	if err != nil {
+		return nil, err
+	}
+	maskTree(tree, reflect.TypeOf(cfg), FileTypeYAML, o.naming)
+	return tree, nil
+}
+
+// maskTree 隐藏配置树中敏感配置项的值，配置树中的键为配置文档中的键
+func maskTree(v interface{}, t reflect.Type, typ FileType, naming Naming) {
+	if t != nil {
+		t = indirectType(t)
+	}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		var fields []field
+		if t != nil && t.Kind() == reflect.Struct {
+			fields = structFields(t, typ, naming)
+		}
+		for k, e := range v {
+			var et reflect.Type
+			secret := isSecretKey(k)
+			if t != nil && t.Kind() == reflect.Map {
+				et = t.Elem()
+			}
+			for _, f := range fields {
+				if k == f.key || f.fold && strings.EqualFold(k, f.key) {
+					et = f.typ
+					secret = secret || f.secret
+					break
+				}
+			}
+			if secret && e != nil && e != "" {
+				v[k] = MaskedValue
+				continue
+			}
+			maskTree(e, et, typ, naming)
+		}
+	case []interface{}:
+		var et reflect.Type
+		if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			et = t.Elem()
+		}
+		for _, e := range v {
+			maskTree(e, et, typ, naming)
+		}
+	}
+}
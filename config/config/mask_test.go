@@ -0,0 +1,83 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type SecretConfig struct {
+	Username string
+	Password string
+	DSN      string `yaml:"dsn" json:"dsn" secret:"true"`
+	Server   struct {
+		Endpoint string
+		APIToken string `yaml:"api_token" json:"api_token"`
+	}
+}
+
+var secretCfg = func() SecretConfig {
+	cfg := SecretConfig{
+		Username: "user",
+		Password: "pass",
+		DSN:      "root:pass@tcp(127.0.0.1:3306)/db",
+	}
+	cfg.Server.Endpoint = "https://jianghushinian.cn/"
+	cfg.Server.APIToken = "token"
+	return cfg
+}()
+
+func TestMarshalMasked(t *testing.T) {
+	tests := []struct {
+		typ  FileType
+		want map[string]interface{}
+	}{
+		{
+			typ: FileTypeYAML,
+			want: map[string]interface{}{
+				"username": "user",
+				"password": MaskedValue,
+				"dsn":      MaskedValue,
+				"server": map[string]interface{}{
+					"endpoint":  "https://jianghushinian.cn/",
+					"api_token": MaskedValue,
+				},
+			},
+		},
+		{
+			typ: FileTypeJSON,
+			want: map[string]interface{}{
+				"Username": "user",
+				"Password": MaskedValue,
+				"dsn":      MaskedValue,
+				"Server": map[string]interface{}{
+					"Endpoint":  "https://jianghushinian.cn/",
+					"api_token": MaskedValue,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typ.String(), func(t *testing.T) {
+			data, err := MarshalMasked(&secretCfg, tt.typ)
+			assert.NoError(t, err)
+
+			tree, err := unmarshalTree(data, tt.typ)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, plainTree(tree))
+		})
+	}
+}
+
+func TestMarshalMaskedMap(t *testing.T) {
+	cfg := map[string]interface{}{
+		"db": map[string]interface{}{
+			"user":     "root",
+			"password": "pass",
+		},
+		"auth_token": "",
+	}
+	data, err := MarshalMasked(cfg, FileTypeJSON)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"auth_token": "", "db": {"password": "******", "user": "root"}}`, string(data))
+}
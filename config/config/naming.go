@@ -0,0 +1,157 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// Naming 配置键命名风格
+type Naming int
+
+const (
+	// NamingDefault 沿用各格式解码器自身的规则：
+	// YAML 为小写的字段名，JSON、TOML 为大小写不敏感的字段名
+	NamingDefault Naming = iota
+	// NamingExact 与字段名完全一致，如 EndpointURL
+	NamingExact
+	// NamingSnakeCase 如 endpoint_url
+	NamingSnakeCase
+	// NamingCamelCase 如 endpointURL
+	NamingCamelCase
+	// NamingKebabCase 如 endpoint-url
+	NamingKebabCase
+)
+
+func (n Naming) String() string {
+	switch n {
+	case NamingDefault:
+		return "default"
+	case NamingExact:
+		return "exact"
+	case NamingSnakeCase:
+		return "snake_case"
+	case NamingCamelCase:
+		return "camelCase"
+	case NamingKebabCase:
+		return "kebab-case"
+	}
+	return "unknown"
+}
+
+// key 根据命名风格将字段名转换为配置键
+func (n Naming) key(name string) string {
+	switch n {
+	case NamingSnakeCase:
+		return strings.ToLower(strings.Join(splitWords(name), "_"))
+	case NamingKebabCase:
+		return strings.ToLower(strings.Join(splitWords(name), "-"))
+	case NamingCamelCase:
+		words := splitWords(name)
+		if len(words) == 0 {
+			return name
+		}
+		words[0] = strings.ToLower(words[0])
+		return strings.Join(words, "")
+	}
+	return name
+}
+
+// splitWords 按照 Go 命名习惯拆分单词，连续的大写字母视为一个缩写词
+// 如 EndpointURL => [Endpoint URL]，HTTPServer => [HTTP Server]
+func splitWords(name string) []string {
+	var (
+		words []string
+		start int
+	)
+	runes := []rune(name)
+	for i := 1; i < len(runes); i++ {
+		prev, cur := runes[i-1], runes[i]
+		var next rune
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+		if cur == '_' {
+			if start < i {
+				words = append(words, string(runes[start:i]))
+			}
+			start = i + 1
+			continue
+		}
+		if unicode.IsUpper(cur) && (unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+			unicode.IsUpper(prev) && unicode.IsLower(next)) {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	if start < len(runes) {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+// normalize 将配置树中的键统一映射为解码器识别的键，同时处理字段别名
+func normalize(tree interface{}, t reflect.Type, typ FileType, naming Naming) (changed bool, warnings []string, err error) {
+	err = walkTree(tree, t, typ, naming, "", func(m map[string]interface{}, fields []field, path string) error {
+		out := make(map[string]interface{}, len(m))
+		used := make(map[string]struct{}, len(fields))
+		for _, f := range fields {
+			key, aliased, warning, err := resolveAlias(m, f, path)
+			if err != nil {
+				return err
+			}
+			if warning != "" {
+				warnings = append(warnings, warning)
+			}
+			if key == "" || naming == NamingDefault && !aliased {
+				continue
+			}
+			out[f.native] = m[key]
+			used[key] = struct{}{}
+			if key != f.native {
+				changed = true
+			}
+		}
+		for k, v := range m {
+			if _, ok := used[k]; ok {
+				continue
+			}
+			// 不符合命名风格，却会被解码器识别的键需要移除
+			if naming != NamingDefault && matchNative(k, fields, typ) {
+				changed = true
+				continue
+			}
+			out[k] = v
+		}
+		for k := range m {
+			delete(m, k)
+		}
+		for k, v := range out {
+			m[k] = v
+		}
+		return nil
+	})
+	return changed, warnings, err
+}
+
+func matchNative(key string, fields []field, typ FileType) bool {
+	for _, f := range fields {
+		if key == f.native || typ != FileTypeYAML && strings.EqualFold(key, f.native) {
+			return true
+		}
+	}
+	return false
+}
+
+// denormalize 将配置树中解码器识别的键转换为命名风格对应的键，用于导出配置
+func denormalize(tree interface{}, t reflect.Type, typ FileType, naming Naming) error {
+	return walkTree(tree, t, typ, naming, "", func(m map[string]interface{}, fields []field, path string) error {
+		for _, f := range fields {
+			if v, ok := m[f.native]; ok && f.key != f.native {
+				delete(m, f.native)
+				m[f.key] = v
+			}
+		}
+		return nil
+	})
+}
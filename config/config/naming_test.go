@@ -0,0 +1,132 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type NamingConfig struct {
+	Username    string
+	MaxIdleConn int
+	Server      struct {
+		EndpointURL string
+		HTTPPort    int `yaml:"port" json:"port"`
+	}
+}
+
+var expNamingCfg = NamingConfig{
+	Username:    "user",
+	MaxIdleConn: 10,
+	Server: struct {
+		EndpointURL string
+		HTTPPort    int `yaml:"port" json:"port"`
+	}{
+		EndpointURL: "https://jianghushinian.cn/",
+		HTTPPort:    8080,
+	},
+}
+
+func TestNamingKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		naming Naming
+		want   string
+	}{
+		{name: "EndpointURL", naming: NamingSnakeCase, want: "endpoint_url"},
+		{name: "HTTPServer", naming: NamingSnakeCase, want: "http_server"},
+		{name: "MaxIdleConn", naming: NamingKebabCase, want: "max-idle-conn"},
+		{name: "Server2Addr", naming: NamingKebabCase, want: "server2-addr"},
+		{name: "EndpointURL", naming: NamingCamelCase, want: "endpointURL"},
+		{name: "HTTPServer", naming: NamingCamelCase, want: "httpServer"},
+		{name: "ID", naming: NamingCamelCase, want: "id"},
+		{name: "EndpointURL", naming: NamingExact, want: "EndpointURL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.naming.String()+"/"+tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.naming.key(tt.name))
+		})
+	}
+}
+
+func TestLoadConfigWithNaming(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name   string
+		naming Naming
+		typ    FileType
+		data   string
+	}{
+		{
+			name:   "snake_case yaml",
+			naming: NamingSnakeCase,
+			typ:    FileTypeYAML,
+			data:   "username: user\nmax_idle_conn: 10\nserver:\n  endpoint_url: https://jianghushinian.cn/\n  port: 8080\n",
+		},
+		{
+			name:   "snake_case json",
+			naming: NamingSnakeCase,
+			typ:    FileTypeJSON,
+			data:   `{"username": "user", "max_idle_conn": 10, "server": {"endpoint_url": "https://jianghushinian.cn/", "port": 8080}}`,
+		},
+		{
+			name:   "camelCase yaml",
+			naming: NamingCamelCase,
+			typ:    FileTypeYAML,
+			data:   "username: user\nmaxIdleConn: 10\nserver:\n  endpointURL: https://jianghushinian.cn/\n  port: 8080\n",
+		},
+		{
+			name:   "kebab-case json",
+			naming: NamingKebabCase,
+			typ:    FileTypeJSON,
+			data:   `{"username": "user", "max-idle-conn": 10, "server": {"endpoint-url": "https://jianghushinian.cn/", "port": 8080}}`,
+		},
+		{
+			name:   "exact yaml",
+			naming: NamingExact,
+			typ:    FileTypeYAML,
+			data:   "Username: user\nMaxIdleConn: 10\nServer:\n  EndpointURL: https://jianghushinian.cn/\n  port: 8080\n",
+		},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(dir, tt.naming.String()+string(rune('0'+i)))
+			_ = os.WriteFile(filename, []byte(tt.data), 0644)
+
+			var cfg NamingConfig
+			err := LoadConfig(filename, &cfg, tt.typ, WithNaming(tt.naming))
+			assert.NoError(t, err)
+			assert.Equal(t, expNamingCfg, cfg)
+		})
+	}
+}
+
+func TestLoadConfigWithNamingIgnoresNativeKeys(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "config.json")
+	_ = os.WriteFile(filename, []byte(`{"username": "user", "MaxIdleConn": 10, "maxidleconn": 10}`), 0644)
+
+	var cfg NamingConfig
+	err := LoadJSONConfig(filename, &cfg, WithNaming(NamingSnakeCase))
+	assert.NoError(t, err)
+	assert.Equal(t, NamingConfig{Username: "user"}, cfg)
+}
+
+func TestDumpConfigWithNaming(t *testing.T) {
+	for _, typ := range []FileType{FileTypeYAML, FileTypeJSON} {
+		filename := filepath.Join(t.TempDir(), "config")
+
+		err := DumpConfig(filename, &expNamingCfg, typ, WithNaming(NamingSnakeCase))
+		assert.NoError(t, err)
+
+		data, _ := os.ReadFile(filename)
+		assert.Contains(t, string(data), "max_idle_conn")
+		assert.Contains(t, string(data), "endpoint_url")
+
+		var cfg NamingConfig
+		err = LoadConfig(filename, &cfg, typ, WithNaming(NamingSnakeCase))
+		assert.NoError(t, err)
+		assert.Equal(t, expNamingCfg, cfg)
+	}
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"flag"
+	"os"
+)
+
+// Option 加载配置选项
+type Option func(*options)
+
+type options struct {
+	filename       string
+	fileType       *FileType
+	warningHandler func(msg string)
+	naming         Naming
+	envPrefix      string
+	lookupEnv      func(key string) (string, bool)
+	overrides      map[string]string
+	validators     []func(cfg interface{}) error
+	validate       bool
+	lastKnownGood  string
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		lookupEnv: os.LookupEnv,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithWarningHandler 设置加载配置时产生告警（如使用了已废弃的键）的处理函数
+func WithWarningHandler(fn func(msg string)) Option {
+	return func(o *options) {
+		o.warningHandler = fn
+	}
+}
+
+// WithNaming 设置配置键的命名风格，统一作用于所有格式的配置文件、环境变量以及导出的配置
+// 通过 yaml、json 标签显式指定的键名不受影响
+func WithNaming(naming Naming) Option {
+	return func(o *options) {
+		o.naming = naming
+	}
+}
+
+// WithEnvPrefix 使用指定前缀的环境变量覆盖配置文件中的值
+// 如前缀为 APP 时，环境变量 APP_SERVER__ENDPOINT 会覆盖 server.endpoint 的值
+func WithEnvPrefix(prefix string) Option {
+	return func(o *options) {
+		o.envPrefix = prefix
+	}
+}
+
+// WithLastKnownGood 将最近一次加载并校验成功的配置及其摘要保存到 filename
+// 首次加载配置失败（读取、解析或校验失败）时，会使用保存的配置启动，
+// 失败原因以及当前生效配置的摘要可以通过 Loader.Status 获取，同时会开启加载后校验（参考 WithValidation）
+func WithLastKnownGood(filename string) Option {
+	return func(o *options) {
+		o.validate = true
+		o.lastKnownGood = filename
+	}
+}
+
+// WithEnvLookup 设置查找环境变量的函数，默认为 os.LookupEnv
+// 可以在测试中使用，避免修改进程的环境变量
+func WithEnvLookup(lookup func(key string) (string, bool)) Option {
+	return func(o *options) {
+		o.lookupEnv = lookup
+	}
+}
+
+// WithFile 指定配置文件，用于 Load、MustLoad 以及 NewStore
+// 未指定时使用命令行参数 -c 指定的配置文件
+func WithFile(filename string) Option {
+	return func(o *options) {
+		o.filename = filename
+	}
+}
+
+// WithFileType 指定配置文件类型，用于 Load、MustLoad 以及 NewStore
+// 未指定时根据文件扩展名推断
+func WithFileType(typ FileType) Option {
+	return func(o *options) {
+		o.fileType = &typ
+	}
+}
+
+// source 返回配置文件及其类型
+func (o options) source() (string, FileType, error) {
+	filename := o.filename
+	if filename == "" {
+		if !flag.Parsed() {
+			flag.Parse()
+		}
+		filename = *cfgPath
+	}
+	if o.fileType != nil {
+		return filename, *o.fileType, nil
+	}
+	typ, err := FileTypeOf(filename)
+	return filename, typ, err
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// WithOverrides 使用 key=value 形式的值（如来自命令行参数）覆盖配置，优先级高于环境变量
+// key 为配置文档中以 "." 分隔的键路径，如 server.endpoint
+func WithOverrides(overrides map[string]string) Option {
+	return func(o *options) {
+		if o.overrides == nil {
+			o.overrides = make(map[string]string, len(overrides))
+		}
+		for k, v := range overrides {
+			o.overrides[k] = v
+		}
+	}
+}
+
+// applyOverrides 按照结构体类型将覆盖值写入配置树中解码器识别的键
+func applyOverrides(m map[string]interface{}, t reflect.Type, typ FileType, naming Naming, overrides map[string]string) error {
+	for path, v := range overrides {
+		if err := applyOverride(m, t, typ, naming, strings.Split(path, "."), v); err != nil {
+			return fmt.Errorf("override %q: %w", path, err)
+		}
+	}
+	return nil
+}
+
+func applyOverride(m map[string]interface{}, t reflect.Type, typ FileType, naming Naming, keys []string, v string) error {
+	t = indirectType(t)
+	var (
+		key string
+		ft  reflect.Type
+	)
+	switch t.Kind() {
+	case reflect.Struct:
+		for _, f := range structFields(t, typ, naming) {
+			if keys[0] == f.key || f.fold && strings.EqualFold(keys[0], f.key) {
+				key, ft = f.native, f.typ
+				if k, ok := lookupKey(m, f.native, f.fold); ok {
+					key = k
+				}
+				break
+			}
+		}
+		if ft == nil {
+			return fmt.Errorf("unknown config key %q", keys[0])
+		}
+	case reflect.Map:
+		key, ft = keys[0], t.Elem()
+	default:
+		return fmt.Errorf("config key %q is not a map", keys[0])
+	}
+
+	if len(keys) == 1 {
+		m[key] = parseValue(v, indirectType(ft))
+		return nil
+	}
+	sub, ok := m[key].(map[string]interface{})
+	if !ok {
+		sub = make(map[string]interface{})
+		m[key] = sub
+	}
+	return applyOverride(sub, ft, typ, naming, keys[1:], v)
+}
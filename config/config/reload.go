@@ -0,0 +1,95 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// ReloadResult 一次重新加载配置的结果
+type ReloadResult struct {
+	Success          bool     `json:"success"`
+	Version          string   `json:"version"`                    // 当前生效配置的摘要
+	Changes          []Change `json:"changes,omitempty"`          // 本次重新加载应用的配置差异，敏感配置项的值会被隐藏
+	ValidationErrors []string `json:"validationErrors,omitempty"` // 配置校验失败的原因
+	Error            string   `json:"error,omitempty"`            // 加载配置失败的原因
+	Warnings         []string `json:"warnings,omitempty"`
+
+	err error
+}
+
+// Err 返回重新加载配置失败的错误
+func (r *ReloadResult) Err() error {
+	return r.err
+}
+
+func newReloadResult(l *Loader, err error) *ReloadResult {
+	r := &ReloadResult{
+		Success:  err == nil,
+		Version:  l.Status().Checksum,
+		Warnings: l.Warnings(),
+		err:      err,
+	}
+	if err == nil {
+		return r
+	}
+	r.Error = err.Error()
+	var verr *ValidationError
+	if errors.As(err, &verr) {
+		if joined, ok := verr.Err.(interface{ Unwrap() []error }); ok {
+			for _, e := range joined.Unwrap() {
+				r.ValidationErrors = append(r.ValidationErrors, e.Error())
+			}
+		} else {
+			r.ValidationErrors = []string{verr.Err.Error()}
+		}
+	}
+	return r
+}
+
+// ReloadWithResult 重新加载配置并返回结构化的结果，加载失败时保留当前生效的配置
+// 回调函数在释放锁之后调用，回调中可以调用 OnChange、Reload 等方法
+func (s *Store[T]) ReloadWithResult() *ReloadResult {
+	s.mu.Lock()
+	cfg := new(T)
+	if err := s.loader.Load(cfg); err != nil {
+		r := newReloadResult(s.loader, err)
+		s.mu.Unlock()
+		return r
+	}
+	old := s.cfg.Swap(cfg)
+	r := newReloadResult(s.loader, nil)
+	r.Changes, _ = diff(old, cfg, s.loader.opts)
+	listeners := append([]func(old, new *T){}, s.listeners...)
+	s.mu.Unlock()
+
+	for _, fn := range listeners {
+		fn(old, cfg)
+	}
+	return r
+}
+
+// ReloadOnSignal 收到信号时重新加载配置，并将结果传给 fn，直到 ctx 结束
+// 未指定信号时为 SIGHUP，fn 可以为 nil
+func (s *Store[T]) ReloadOnSignal(ctx context.Context, fn func(*ReloadResult), sig ...os.Signal) {
+	if len(sig) == 0 {
+		sig = []os.Signal{syscall.SIGHUP}
+	}
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, sig...)
+	defer signal.Stop(c)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c:
+			r := s.ReloadWithResult()
+			if fn != nil {
+				fn(r)
+			}
+		}
+	}
+}
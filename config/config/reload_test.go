@@ -0,0 +1,128 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStore(t *testing.T, opts ...Option) (*Store[ValidatedConfig], string) {
+	filename := filepath.Join(t.TempDir(), "config.yaml")
+	data, _ := os.ReadFile("testdata/config.yaml")
+	require.NoError(t, os.WriteFile(filename, data, 0644))
+
+	s, err := NewStore[ValidatedConfig](append([]Option{WithFile(filename), WithValidation()}, opts...)...)
+	require.NoError(t, err)
+	return s, filename
+}
+
+func TestStoreReloadWithResult(t *testing.T) {
+	s, filename := newTestStore(t)
+	version := s.Loader().Status().Checksum
+
+	require.NoError(t, os.WriteFile(filename, []byte("username: admin\n"), 0644))
+	r := s.ReloadWithResult()
+	assert.False(t, r.Success)
+	assert.ErrorIs(t, r.Err(), errEmptyEndpoint)
+	assert.Equal(t, []string{errEmptyEndpoint.Error()}, r.ValidationErrors)
+	assert.Equal(t, version, r.Version)
+	assert.Equal(t, "user", s.Get().Username)
+
+	require.NoError(t, os.WriteFile(filename, []byte("username: admin\npassword: secret\nserver:\n  endpoint: https://jianghushinian.cn/\n"), 0644))
+	r = s.ReloadWithResult()
+	assert.True(t, r.Success)
+	assert.NoError(t, r.Err())
+	assert.Empty(t, r.ValidationErrors)
+	assert.NotEqual(t, version, r.Version)
+	assert.Equal(t, []string{
+		"~ password: ****** -> ******",
+		"~ username: user -> admin",
+	}, changeStrings(r.Changes))
+}
+
+func TestStoreReloadListener(t *testing.T) {
+	s, filename := newTestStore(t)
+	var calls int
+	s.OnChange(func(old, new *ValidatedConfig) {
+		calls++
+		// 回调中可以注册新的回调，新的回调在下次重新加载时调用
+		s.OnChange(func(old, new *ValidatedConfig) { calls++ })
+		assert.Equal(t, new, s.Get())
+	})
+
+	require.NoError(t, os.WriteFile(filename, []byte("username: admin\nserver:\n  endpoint: https://jianghushinian.cn/\n"), 0644))
+	done := make(chan *ReloadResult)
+	go func() { done <- s.ReloadWithResult() }()
+	select {
+	case r := <-done:
+		assert.True(t, r.Success)
+	case <-time.After(time.Second):
+		t.Fatal("reload deadlock")
+	}
+	assert.Equal(t, 1, calls)
+	require.NoError(t, s.Reload())
+	assert.Equal(t, 3, calls)
+}
+
+func TestStoreReloadWithJoinedValidationErrors(t *testing.T) {
+	errA, errB := errors.New("a is required"), errors.New("b is required")
+	s, filename := newTestStore(t, WithValidator(func(cfg interface{}) error {
+		if cfg.(*ValidatedConfig).Username == "admin" {
+			return errors.Join(errA, errB)
+		}
+		return nil
+	}))
+
+	require.NoError(t, os.WriteFile(filename, []byte("username: admin\nserver:\n  endpoint: https://jianghushinian.cn/\n"), 0644))
+	r := s.ReloadWithResult()
+	assert.False(t, r.Success)
+	assert.Equal(t, []string{"a is required", "b is required"}, r.ValidationErrors)
+}
+
+func TestStoreHandler(t *testing.T) {
+	s, filename := newTestStore(t)
+	h := s.Handler()
+
+	serve := func(method, target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(method, target, nil))
+		return w
+	}
+
+	w := serve(http.MethodGet, "/config")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"Username": "user", "Password": "******", "Server": {"Endpoint": "https://jianghushinian.cn/"}}`, w.Body.String())
+
+	w = serve(http.MethodGet, "/config/version")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var version struct {
+		Version string
+		Status  Status
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &version))
+	assert.Equal(t, s.Loader().Status().Checksum, version.Version)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, serve(http.MethodGet, "/config/reload").Code)
+
+	require.NoError(t, os.WriteFile(filename, []byte("username: admin\n"), 0644))
+	w = serve(http.MethodPost, "/config/reload")
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.True(t, strings.Contains(w.Body.String(), `"validationErrors":["server.endpoint is required"]`), w.Body.String())
+
+	require.NoError(t, os.WriteFile(filename, []byte("username: admin\nserver:\n  endpoint: https://jianghushinian.cn/\n"), 0644))
+	w = serve(http.MethodPost, "/config/reload")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result ReloadResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.True(t, result.Success)
+	assert.Equal(t, []string{"~ password: ****** -> ", "~ username: user -> admin"}, changeStrings(result.Changes))
+}
@@ -0,0 +1,34 @@
+//go:build unix
+
+package config
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreReloadOnSignal(t *testing.T) {
+	s, filename := newTestStore(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	results := make(chan *ReloadResult, 1)
+	go s.ReloadOnSignal(ctx, func(r *ReloadResult) { results <- r }, syscall.SIGUSR1)
+	time.Sleep(10 * time.Millisecond)
+
+	require.NoError(t, os.WriteFile(filename, []byte("username: admin\nserver:\n  endpoint: https://jianghushinian.cn/\n"), 0644))
+	require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGUSR1))
+	select {
+	case r := <-results:
+		assert.True(t, r.Success)
+		assert.Equal(t, "admin", s.Get().Username)
+	case <-time.After(time.Second):
+		t.Fatal("config was not reloaded")
+	}
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+var (
+	schemasMu sync.RWMutex
+	schemas   = make(map[string]func() interface{})
+)
+
+// RegisterSchema 注册配置结构体，newFn 返回新的配置结构体指针
+// 注册后可以通过 gokit-config 等工具按名称校验、渲染配置文件，重复注册同一名称会 panic
+func RegisterSchema(name string, newFn func() interface{}) {
+	schemasMu.Lock()
+	defer schemasMu.Unlock()
+	if newFn == nil {
+		panic("config: RegisterSchema newFn is nil")
+	}
+	if _, dup := schemas[name]; dup {
+		panic(fmt.Sprintf("config: RegisterSchema called twice for schema %s", name))
+	}
+	schemas[name] = newFn
+}
+
+// LookupSchema 根据名称查找已注册的配置结构体
+func LookupSchema(name string) (newFn func() interface{}, ok bool) {
+	schemasMu.RLock()
+	defer schemasMu.RUnlock()
+	newFn, ok = schemas[name]
+	return newFn, ok
+}
+
+// Schemas 返回已注册的配置结构体名称
+func Schemas() []string {
+	schemasMu.RLock()
+	defer schemasMu.RUnlock()
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,178 @@
+package config
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// readSource 读取配置内容，目录类型的配置会直接解析为配置树
+func readSource(filename string, typ FileType) (data []byte, tree interface{}, err error) {
+	if typ != FileTypeDir {
+		data, err = os.ReadFile(filename)
+		if err != nil {
+			return nil, nil, fmt.Errorf("ReadFile: %v", err)
+		}
+		return data, nil, nil
+	}
+
+	m := make(map[string]interface{})
+	var buf bytes.Buffer
+	if err = readDir(filename, nil, m, &buf); err != nil {
+		return nil, nil, fmt.Errorf("ReadDir: %v", err)
+	}
+	return buf.Bytes(), m, nil
+}
+
+// readDir 读取一个文件对应一个键的目录，如 Kubernetes 以卷方式挂载的 ConfigMap、Secret
+// 文件名中的 "__" 或 "." 表示嵌套的键，子目录同样表示嵌套的键，其中 "." 由 expandDirKeys 按照配置类型拆分
+// 以 "." 开头的文件会被忽略，Kubernetes 通过 ..data 符号链接原子地切换配置内容，
+// 顶层的文件本身是指向 ..data 中同名文件的符号链接，因此读取时会跟随符号链接
+// 读取的内容会按文件名排序写入 buf，用于计算配置摘要
+func readDir(dir string, prefix []string, m map[string]interface{}, buf *bytes.Buffer) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	for _, e := range entries {
+		name := e.Name()
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		keys := append(append([]string{}, prefix...), strings.Split(name, "__")...)
+		if info.IsDir() {
+			if err = readDir(path, keys, m, buf); err != nil {
+				return err
+			}
+			continue
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		setPath(m, keys, strings.TrimRight(string(content), "\r\n"))
+
+		buf.WriteString(strings.Join(keys, "."))
+		buf.WriteByte(0)
+		buf.Write(content)
+		buf.WriteByte(0)
+	}
+	return nil
+}
+
+func setPath(m map[string]interface{}, keys []string, v interface{}) {
+	for _, k := range keys[:len(keys)-1] {
+		sub, ok := m[k].(map[string]interface{})
+		if !ok {
+			sub = make(map[string]interface{})
+			m[k] = sub
+		}
+		m = sub
+	}
+	m[keys[len(keys)-1]] = v
+}
+
+// expandDirKeys 将目录配置中包含 "." 的键拆分为嵌套的键，如 server.port 对应 server 下的 port，
+// 结构体中存在与完整键对应的字段（如通过 yaml:"tls.crt" 标签指定）时保留原键
+func expandDirKeys(tree interface{}, t reflect.Type, typ FileType, naming Naming) {
+	m, ok := tree.(map[string]interface{})
+	if !ok {
+		return
+	}
+	if t != nil {
+		t = indirectType(t)
+	}
+	var fields []field
+	if t != nil && t.Kind() == reflect.Struct {
+		fields = structFields(t, typ, naming)
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if !strings.Contains(k, ".") || findField(fields, k) != nil {
+			continue
+		}
+		v := m[k]
+		delete(m, k)
+		setPath(m, strings.SplitN(k, ".", 2), v)
+	}
+	for k, v := range m {
+		var sub reflect.Type
+		switch {
+		case fields != nil:
+			if f := findField(fields, k); f != nil {
+				sub = f.typ
+			}
+		case t != nil && t.Kind() == reflect.Map:
+			sub = t.Elem()
+		}
+		if fields != nil && sub == nil {
+			// 没有对应字段的键保持不变，由 Lint 报告
+			continue
+		}
+		expandDirKeys(v, sub, typ, naming)
+	}
+}
+
+func findField(fields []field, key string) *field {
+	for i, f := range fields {
+		if !f.inline && (f.key == key || f.native == key) {
+			return &fields[i]
+		}
+	}
+	return nil
+}
+
+// convertValues 目录中读取的值均为字符串，按照字段类型转换为对应的值
+func convertValues(tree interface{}, t reflect.Type, typ FileType, naming Naming) error {
+	return walkTree(tree, t, typ, naming, "", func(m map[string]interface{}, fields []field, path string) error {
+		for _, f := range fields {
+			if v, ok := m[f.native]; ok {
+				m[f.native] = convertValue(v, f.typ)
+			}
+		}
+		return nil
+	})
+}
+
+// convertValue 按照类型 t 转换字符串，以及映射、切片中的字符串，映射、切片中的结构体由 walkTree 处理
+func convertValue(v interface{}, t reflect.Type) interface{} {
+	t = indirectType(t)
+	switch v := v.(type) {
+	case string:
+		return parseValue(v, t)
+	case map[string]interface{}:
+		if t.Kind() == reflect.Map {
+			for k, e := range v {
+				v[k] = convertValue(e, t.Elem())
+			}
+		}
+	case []interface{}:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for i, e := range v {
+				v[i] = convertValue(e, t.Elem())
+			}
+		}
+	}
+	return v
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
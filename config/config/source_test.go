@@ -0,0 +1,120 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeConfigMap 按照 Kubernetes 挂载 ConfigMap 的目录结构写入配置
+func writeConfigMap(t *testing.T, dir, version string, files map[string]string) {
+	t.Helper()
+	data := filepath.Join(dir, version)
+	require.NoError(t, os.Mkdir(data, 0755))
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(data, name), []byte(content), 0644))
+	}
+
+	// 原子地切换 ..data 符号链接
+	tmp := filepath.Join(dir, "..data_tmp")
+	require.NoError(t, os.Symlink(version, tmp))
+	require.NoError(t, os.Rename(tmp, filepath.Join(dir, "..data")))
+
+	for name := range files {
+		link := filepath.Join(dir, name)
+		if _, err := os.Lstat(link); os.IsNotExist(err) {
+			require.NoError(t, os.Symlink(filepath.Join("..data", name), link))
+		}
+	}
+}
+
+func TestLoadDirConfig(t *testing.T) {
+	type DirConfig struct {
+		Username string
+		Password string
+		Server   struct {
+			Endpoint string
+			Port     int
+			Debug    bool
+		}
+		TLSCert string         `yaml:"tls.crt"`
+		Limits  map[string]int `yaml:"limits"`
+		Weights []float64      `yaml:"weights"`
+	}
+
+	dir := t.TempDir()
+	writeConfigMap(t, dir, "..2023_04_16_00_00_00.1", map[string]string{
+		"username":         "user\n",
+		"password":         "p@ss: 123",
+		"server__endpoint": "https://jianghushinian.cn/",
+		"server.port":      "8080",
+		"server.debug":     "true",
+		"tls.crt":          "-----BEGIN CERTIFICATE-----\n",
+		"limits__read":     "100",
+		"limits__write":    "10",
+		"limits.delete":    "1",
+		"weights":          "[0.5, 1]",
+	})
+
+	var cfg DirConfig
+	err := LoadConfig(dir, &cfg, FileTypeDir)
+	assert.NoError(t, err)
+	assert.Equal(t, "user", cfg.Username)
+	assert.Equal(t, "p@ss: 123", cfg.Password)
+	assert.Equal(t, "https://jianghushinian.cn/", cfg.Server.Endpoint)
+	assert.Equal(t, 8080, cfg.Server.Port)
+	assert.True(t, cfg.Server.Debug)
+	assert.Equal(t, "-----BEGIN CERTIFICATE-----", cfg.TLSCert)
+	assert.Equal(t, map[string]int{"read": 100, "write": 10, "delete": 1}, cfg.Limits)
+	assert.Equal(t, []float64{0.5, 1}, cfg.Weights)
+
+	// 没有与完整文件名对应的字段时，"." 表示嵌套的键
+	var m map[string]interface{}
+	require.NoError(t, LoadConfig(dir, &m, FileTypeDir))
+	assert.Equal(t, map[string]interface{}{"port": "8080", "debug": "true", "endpoint": "https://jianghushinian.cn/"}, m["server"])
+	assert.Equal(t, map[string]interface{}{"crt": "-----BEGIN CERTIFICATE-----"}, m["tls"])
+}
+
+func TestLoaderWatchDir(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"username":         "user",
+		"password":         "pass",
+		"server__endpoint": "https://jianghushinian.cn/",
+	}
+	writeConfigMap(t, dir, "..1", files)
+
+	l := NewLoader(dir, FileTypeDir)
+	var cfg Config
+	require.NoError(t, l.Load(&cfg))
+	assert.Equal(t, expCfg, cfg)
+
+	changed, err := l.Changed()
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reloaded := make(chan Config, 1)
+	go l.Watch(ctx, 10*time.Millisecond, func() {
+		var cfg Config
+		if err := l.Load(&cfg); err == nil {
+			reloaded <- cfg
+		}
+	})
+
+	files["password"] = "new-pass"
+	writeConfigMap(t, dir, "..2", files)
+
+	select {
+	case cfg = <-reloaded:
+		assert.Equal(t, "new-pass", cfg.Password)
+	case <-time.After(time.Second):
+		t.Fatal("config was not reloaded")
+	}
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Store 持有类型为 T 的当前生效配置，支持并发读取以及热加载
+type Store[T any] struct {
+	loader *Loader
+	cfg    atomic.Pointer[T]
+
+	mu        sync.Mutex
+	listeners []func(old, new *T)
+}
+
+// NewStore 加载配置并创建 Store，选项与 Load 相同
+func NewStore[T any](opts ...Option) (*Store[T], error) {
+	filename, typ, err := newOptions(opts).source()
+	if err != nil {
+		return nil, err
+	}
+	s := &Store[T]{loader: NewLoader(filename, typ, opts...)}
+	cfg := new(T)
+	if err = s.loader.Load(cfg); err != nil {
+		return nil, err
+	}
+	s.cfg.Store(cfg)
+	return s, nil
+}
+
+// Get 返回当前生效的配置，调用方不应修改返回的配置
+func (s *Store[T]) Get() *T {
+	return s.cfg.Load()
+}
+
+// Loader 返回 Store 使用的配置加载器，可用于获取告警以及加载状态
+func (s *Store[T]) Loader() *Loader {
+	return s.loader
+}
+
+// OnChange 注册配置变更回调，重新加载配置成功后调用
+func (s *Store[T]) OnChange(fn func(old, new *T)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listeners = append(s.listeners, fn)
+}
+
+// Reload 重新加载配置，加载失败时保留当前生效的配置
+func (s *Store[T]) Reload() error {
+	return s.ReloadWithResult().Err()
+}
+
+// Watch 每隔 interval 检查一次配置内容，内容变化时重新加载配置，直到 ctx 结束
+func (s *Store[T]) Watch(ctx context.Context, interval time.Duration) {
+	s.loader.Watch(ctx, interval, func() {
+		_ = s.Reload()
+	})
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "config.yaml")
+	data, _ := os.ReadFile("testdata/config.yaml")
+	require.NoError(t, os.WriteFile(filename, data, 0644))
+
+	s, err := NewStore[ValidatedConfig](WithFile(filename), WithValidation())
+	require.NoError(t, err)
+	assert.Equal(t, "user", s.Get().Username)
+
+	changed := make(chan [2]*ValidatedConfig, 1)
+	s.OnChange(func(old, new *ValidatedConfig) {
+		changed <- [2]*ValidatedConfig{old, new}
+	})
+
+	// 校验失败时保留当前生效的配置
+	require.NoError(t, os.WriteFile(filename, []byte("username: admin\n"), 0644))
+	assert.ErrorIs(t, s.Reload(), errEmptyEndpoint)
+	assert.Equal(t, "user", s.Get().Username)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Watch(ctx, 10*time.Millisecond)
+
+	require.NoError(t, os.WriteFile(filename, []byte("username: admin\nserver:\n  endpoint: https://jianghushinian.cn/\n"), 0644))
+	select {
+	case cfgs := <-changed:
+		assert.Equal(t, "user", cfgs[0].Username)
+		assert.Equal(t, "admin", cfgs[1].Username)
+		assert.Equal(t, cfgs[1], s.Get())
+	case <-time.After(time.Second):
+		t.Fatal("config was not reloaded")
+	}
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"flag"
+	"os"
+)
+
+func LoadTOMLConfig(filename string, cfg interface{}, opts ...Option) error {
+	return LoadConfig(filename, cfg, FileTypeTOML, opts...)
+}
+
+func LoadTOMLConfigFromFlag(cfg interface{}, opts ...Option) error {
+	if !flag.Parsed() {
+		flag.Parse()
+	}
+	return LoadTOMLConfig(*cfgPath, cfg, opts...)
+}
+
+func DumpTOMLConfig(filename string, cfg interface{}, opts ...Option) error {
+	return DumpConfig(filename, cfg, FileTypeTOML, opts...)
+}
+
+func DumpTOMLConfigFromFlag(cfg interface{}, opts ...Option) error {
+	if !flag.Parsed() {
+		flag.Parse()
+	}
+	return DumpTOMLConfig(*cfgPath, cfg, opts...)
+}
+
+func LoadOrDumpTOMLConfigFromFlag(cfg interface{}, opts ...Option) error {
+	if *dump {
+		if err := DumpTOMLConfigFromFlag(cfg, opts...); err != nil {
+			return err
+		}
+		os.Exit(0)
+	}
+	return LoadTOMLConfigFromFlag(cfg, opts...)
+}
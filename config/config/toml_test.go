@@ -0,0 +1,53 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadTOMLConfig(t *testing.T) {
+	var cfg Config
+	err := LoadTOMLConfig("testdata/config.toml", &cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, expCfg, cfg)
+}
+
+func TestLoadTOMLConfigFromFlag(t *testing.T) {
+	_ = flag.Set("c", "testdata/config.toml")
+
+	var cfg Config
+	err := LoadTOMLConfigFromFlag(&cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, expCfg, cfg)
+}
+
+func TestDumpTOMLConfig(t *testing.T) {
+	f, _ := os.CreateTemp("", "TEST_DUMP")
+	defer os.Remove(f.Name())
+
+	err := DumpTOMLConfig(f.Name(), &expCfg)
+	assert.NoError(t, err)
+
+	var cfg Config
+	err = LoadTOMLConfig(f.Name(), &cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, expCfg, cfg)
+}
+
+func TestDumpTOMLConfigFromFlag(t *testing.T) {
+	f, _ := os.CreateTemp("", "TEST_DUMP")
+	defer os.Remove(f.Name())
+
+	_ = flag.Set("c", f.Name())
+
+	err := DumpTOMLConfigFromFlag(&expCfg)
+	assert.NoError(t, err)
+
+	var cfg Config
+	err = LoadTOMLConfig(f.Name(), &cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, expCfg, cfg)
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// unmarshalTree 将配置内容解析为通用的配置树
+func unmarshalTree(data []byte, typ FileType) (interface{}, error) {
+	var v interface{}
+	switch typ {
+	case FileTypeYAML:
+		if err := yaml.Unmarshal(data, &v); err != nil {
+			return nil, err
+		}
+	case FileTypeJSON:
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(&v); err != nil {
+			return nil, err
+		}
+	case FileTypeTOML:
+		if err := toml.Unmarshal(data, &v); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("unsupported file type")
+	}
+	return v, nil
+}
+
+// marshalTree 将配置树编码为指定格式
+func marshalTree(v interface{}, typ FileType) ([]byte, error) {
+	switch typ {
+	case FileTypeYAML:
+		return yaml.Marshal(v)
+	case FileTypeJSON:
+		return json.Marshal(v)
+	case FileTypeTOML:
+		return marshalTOML(v)
+	}
+	return nil, errors.New("unsupported file type")
+}
+
+func marshalTOML(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func unmarshal(data []byte, cfg interface{}, typ FileType) error {
+	switch typ {
+	case FileTypeYAML:
+		return yaml.Unmarshal(data, cfg)
+	case FileTypeJSON:
+		return json.Unmarshal(data, cfg)
+	case FileTypeTOML:
+		return toml.Unmarshal(data, cfg)
+	}
+	return errors.New("unsupported file type")
+}
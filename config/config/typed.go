@@ -0,0 +1,29 @@
+package config
+
+// Load 加载配置，返回类型为 T 的配置
+// 配置文件通过 WithFile、WithFileType 选项指定，默认使用命令行参数 -c 指定的配置文件
+func Load[T any](opts ...Option) (*T, error) {
+	cfg := new(T)
+	if err := loadInto(cfg, opts); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// loadInto 按照选项指定的配置文件加载配置到 cfg，Load 以及 LoadConfig、LoadYAMLConfig 使用相同的加载流程
+func loadInto(cfg interface{}, opts []Option) error {
+	filename, typ, err := newOptions(opts).source()
+	if err != nil {
+		return err
+	}
+	return NewLoader(filename, typ, opts...).Load(cfg)
+}
+
+// MustLoad 与 Load 相同，加载失败时 panic
+func MustLoad[T any](opts ...Option) *T {
+	cfg, err := Load[T](opts...)
+	if err != nil {
+		panic(err)
+	}
+	return cfg
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	for _, filename := range []string{"testdata/config.yaml", "testdata/config.json", "testdata/config.toml"} {
+		cfg, err := Load[Config](WithFile(filename))
+		assert.NoError(t, err)
+		assert.Equal(t, &expCfg, cfg)
+	}
+
+	cfg, err := Load[Config](WithFile("testdata/config.json"), WithFileType(FileTypeYAML))
+	assert.NoError(t, err)
+	assert.Equal(t, &expCfg, cfg)
+
+	_, err = Load[Config](WithFile("testdata/config.ini"))
+	assert.EqualError(t, err, "unsupported file type: ini")
+}
+
+func TestMustLoad(t *testing.T) {
+	assert.Equal(t, &expCfg, MustLoad[Config](WithFile("testdata/config.yaml")))
+	assert.Panics(t, func() {
+		MustLoad[Config](WithFile("testdata/not-exist.yaml"))
+	})
+}
+
+func TestLoadConfigUsesLoad(t *testing.T) {
+	// 参数指定的文件及其类型优先于选项
+	var cfg Config
+	assert.NoError(t, LoadConfig("testdata/config.toml", &cfg, FileTypeTOML, WithFile("testdata/not-exist.yaml"), WithFileType(FileTypeYAML)))
+	assert.Equal(t, expCfg, cfg)
+
+	cfg = Config{}
+	assert.NoError(t, LoadYAMLConfig("testdata/config.yaml", &cfg))
+	assert.Equal(t, expCfg, cfg)
+
+	// 与 Load 相同，加载失败时 cfg 保持不变
+	cfg = Config{Username: "old"}
+	assert.Error(t, LoadYAMLConfig("testdata/not-exist.yaml", &cfg))
+	assert.Equal(t, Config{Username: "old"}, cfg)
+}
@@ -0,0 +1,53 @@
+package config
+
+// Validator 配置校验接口，配置结构体实现该接口并且开启校验（参考 WithValidation）时会在加载后校验
+type Validator interface {
+	Validate() error
+}
+
+// ValidationError 配置校验失败
+type ValidationError struct {
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	return "validate config: " + e.Err.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// WithValidation 开启加载后校验，配置结构体实现 Validator 接口时调用其 Validate 方法，
+// 校验失败时返回 *ValidationError，并且传入的配置结构体保持不变
+// 默认不校验，WithValidator、WithLastKnownGood 同样会开启校验
+func WithValidation() Option {
+	return func(o *options) {
+		o.validate = true
+	}
+}
+
+// WithValidator 开启加载后校验，并设置配置校验函数，在配置结构体自身的 Validate 方法之后执行
+func WithValidator(fn func(cfg interface{}) error) Option {
+	return func(o *options) {
+		o.validate = true
+		o.validators = append(o.validators, fn)
+	}
+}
+
+func validate(cfg interface{}, o options) error {
+	if !o.validate {
+		return nil
+	}
+	if v, ok := cfg.(Validator); ok {
+		if err := v.Validate(); err != nil {
+			return &ValidationError{Err: err}
+		}
+	}
+	for _, fn := range o.validators {
+		if err := fn(cfg); err != nil {
+			return &ValidationError{Err: err}
+		}
+	}
+	return nil
+}
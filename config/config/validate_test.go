@@ -0,0 +1,38 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfigValidate(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(filename, []byte("username: user\n"), 0644))
+
+	// 默认不校验，与之前的版本保持一致
+	var cfg ValidatedConfig
+	require.NoError(t, LoadConfig(filename, &cfg, FileTypeYAML))
+	assert.Equal(t, "user", cfg.Username)
+	cfg = ValidatedConfig{}
+	require.NoError(t, LoadYAMLConfig(filename, &cfg))
+	assert.Equal(t, "user", cfg.Username)
+
+	cfg = ValidatedConfig{}
+	err := LoadConfig(filename, &cfg, FileTypeYAML, WithValidation())
+	var verr *ValidationError
+	assert.ErrorAs(t, err, &verr)
+	assert.ErrorIs(t, err, errEmptyEndpoint)
+	assert.Equal(t, ValidatedConfig{}, cfg)
+
+	// WithValidator 同样会开启校验，在 Validate 方法之后执行
+	_, err = Load[ValidatedConfig](WithFile(filename), WithValidator(func(interface{}) error { return nil }))
+	assert.ErrorIs(t, err, errEmptyEndpoint)
+	errUser := errors.New("invalid username")
+	_, err = Load[Config](WithFile(filename), WithValidator(func(interface{}) error { return errUser }))
+	assert.ErrorIs(t, err, errUser)
+}
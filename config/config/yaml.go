@@ -5,34 +5,35 @@ import (
 	"os"
 )
 
-func LoadYAMLConfig(filename string, cfg interface{}) error {
-	return LoadConfig(filename, cfg, FileTypeYAML)
+// LoadYAMLConfig 加载 YAML 格式的配置文件 filename 到 cfg，与 Load 相同
+func LoadYAMLConfig(filename string, cfg interface{}, opts ...Option) error {
+	return LoadConfig(filename, cfg, FileTypeYAML, opts...)
 }
 
-func LoadYAMLConfigFromFlag(cfg interface{}) error {
+func LoadYAMLConfigFromFlag(cfg interface{}, opts ...Option) error {
 	if !flag.Parsed() {
 		flag.Parse()
 	}
-	return LoadYAMLConfig(*cfgPath, cfg)
+	return LoadYAMLConfig(*cfgPath, cfg, opts...)
 }
 
-func DumpYAMLConfig(filename string, cfg interface{}) error {
-	return DumpConfig(filename, cfg, FileTypeYAML)
+func DumpYAMLConfig(filename string, cfg interface{}, opts ...Option) error {
+	return DumpConfig(filename, cfg, FileTypeYAML, opts...)
 }
 
-func DumpYAMLConfigFromFlag(cfg interface{}) error {
+func DumpYAMLConfigFromFlag(cfg interface{}, opts ...Option) error {
 	if !flag.Parsed() {
 		flag.Parse()
 	}
-	return DumpYAMLConfig(*cfgPath, cfg)
+	return DumpYAMLConfig(*cfgPath, cfg, opts...)
 }
 
-func LoadOrDumpYAMLConfigFromFlag(cfg interface{}) error {
+func LoadOrDumpYAMLConfigFromFlag(cfg interface{}, opts ...Option) error {
 	if *dump {
-		if err := DumpYAMLConfigFromFlag(cfg); err != nil {
+		if err := DumpYAMLConfigFromFlag(cfg, opts...); err != nil {
 			return err
 		}
 		os.Exit(0)
 	}
-	return LoadYAMLConfigFromFlag(cfg)
+	return LoadYAMLConfigFromFlag(cfg, opts...)
 }
@@ -0,0 +1,100 @@
+package flags
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Flag 功能开关定义，可以直接使用布尔值定义，如 `new-checkout: true`
+// 评估顺序：
+//  1. Enabled 显式设置为 false 时关闭（紧急开关）
+//  2. key 在 Deny 中时关闭
+//  3. key 在 Allow 中时开启
+//  4. 设置了 Percentage 时，按照 key 的哈希值放量
+//  5. Enabled 为 true 时开启，否则关闭
+type Flag struct {
+	Enabled    *bool    `yaml:"enabled,omitempty" json:"enabled,omitempty" toml:"enabled,omitempty"`
+	Percentage *float64 `yaml:"percentage,omitempty" json:"percentage,omitempty" toml:"percentage,omitempty"` // 放量百分比，0-100
+	Allow      []string `yaml:"allow,omitempty" json:"allow,omitempty" toml:"allow,omitempty"`
+	Deny       []string `yaml:"deny,omitempty" json:"deny,omitempty" toml:"deny,omitempty"`
+}
+
+// Bool 返回使用布尔值定义的功能开关
+func Bool(enabled bool) Flag {
+	return Flag{Enabled: &enabled}
+}
+
+// Percentage 返回按照百分比放量的功能开关
+func Percentage(percentage float64) Flag {
+	return Flag{Percentage: &percentage}
+}
+
+// evaluate 评估功能开关对 key 是否开启，相同的 name、key 总是得到相同的结果
+func (f Flag) evaluate(name, key string) bool {
+	if f.Enabled != nil && !*f.Enabled {
+		return false
+	}
+	for _, k := range f.Deny {
+		if k == key {
+			return false
+		}
+	}
+	for _, k := range f.Allow {
+		if k == key {
+			return true
+		}
+	}
+	if f.Percentage != nil {
+		return float64(bucket(name, key)) < *f.Percentage*100
+	}
+	return f.Enabled != nil && *f.Enabled
+}
+
+// bucket 将 name、key 哈希到 [0, 10000) 区间，放量精度为 0.01%
+func bucket(name, key string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(key))
+	return h.Sum32() % 10000
+}
+
+type flag Flag
+
+func (f *Flag) UnmarshalYAML(node *yaml.Node) error {
+	var enabled bool
+	if node.Kind == yaml.ScalarNode && node.Decode(&enabled) == nil {
+		*f = Bool(enabled)
+		return nil
+	}
+	return node.Decode((*flag)(f))
+}
+
+func (f *Flag) UnmarshalJSON(data []byte) error {
+	var enabled bool
+	if json.Unmarshal(data, &enabled) == nil {
+		*f = Bool(enabled)
+		return nil
+	}
+	return json.Unmarshal(data, (*flag)(f))
+}
+
+// UnmarshalTOML 实现 github.com/BurntSushi/toml.Unmarshaler 接口
+func (f *Flag) UnmarshalTOML(v interface{}) error {
+	switch v := v.(type) {
+	case bool:
+		*f = Bool(v)
+		return nil
+	case map[string]interface{}:
+		// 借助 JSON 解码表格，TOML 的整数、浮点数、字符串数组均能对应到 JSON 类型
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(data, (*flag)(f))
+	}
+	return fmt.Errorf("flags: cannot unmarshal %T into Flag", v)
+}
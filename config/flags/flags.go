@@ -0,0 +1,132 @@
+// Package flags 基于配置的功能开关，支持布尔开关、按百分比放量以及白名单/黑名单
+package flags
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/jianghushinian/gokit/config/config"
+)
+
+// Counts 功能开关的评估次数
+type Counts struct {
+	True  uint64 `json:"true"`
+	False uint64 `json:"false"`
+}
+
+// UnknownFlag 未定义的功能开关共用的评估计数名称，避免为任意名称创建计数
+const UnknownFlag = "<unknown>"
+
+type counter struct {
+	t, f atomic.Uint64
+}
+
+// Set 一组功能开关，支持并发评估以及热更新
+type Set struct {
+	flags atomic.Pointer[map[string]Flag]
+	stats sync.Map // name => *counter
+}
+
+func NewSet(flags map[string]Flag) *Set {
+	s := &Set{}
+	s.Update(flags)
+	return s
+}
+
+// Update 替换全部功能开关定义，评估计数保持不变
+func (s *Set) Update(flags map[string]Flag) {
+	m := make(map[string]Flag, len(flags))
+	for name, f := range flags {
+		m[name] = f
+	}
+	s.flags.Store(&m)
+}
+
+// Enabled 评估功能开关 name 对 key（如用户 ID）是否开启，未定义的功能开关总是关闭
+// 通过 WithOverride 设置到 ctx 中的值优先
+func (s *Set) Enabled(ctx context.Context, name, key string) bool {
+	f, exists := (*s.flags.Load())[name]
+	enabled, ok := override(ctx, name)
+	if !ok && exists {
+		enabled = f.evaluate(name, key)
+	}
+
+	stat := name
+	if !exists {
+		stat = UnknownFlag
+	}
+	c, ok := s.stats.Load(stat)
+	if !ok {
+		c, _ = s.stats.LoadOrStore(stat, new(counter))
+	}
+	if enabled {
+		c.(*counter).t.Add(1)
+	} else {
+		c.(*counter).f.Add(1)
+	}
+	return enabled
+}
+
+// Names 返回已定义的功能开关名称
+func (s *Set) Names() []string {
+	flags := *s.flags.Load()
+	names := make([]string, 0, len(flags))
+	for name := range flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Stats 返回每个功能开关评估结果为 true、false 的次数，未定义的功能开关合并计入 UnknownFlag
+func (s *Set) Stats() map[string]Counts {
+	stats := make(map[string]Counts)
+	s.stats.Range(func(key, value interface{}) bool {
+		c := value.(*counter)
+		stats[key.(string)] = Counts{True: c.t.Load(), False: c.f.Load()}
+		return true
+	})
+	return stats
+}
+
+// Bind 使用 store 中的配置初始化功能开关，并在配置热加载后更新
+func Bind[T any](s *Set, store *config.Store[T], get func(cfg *T) map[string]Flag) {
+	s.Update(get(store.Get()))
+	store.OnChange(func(_, cfg *T) {
+		s.Update(get(cfg))
+	})
+}
+
+type overrideKey struct{}
+
+// WithOverride 返回强制设置功能开关 name 的 ctx，如用于测试或通过请求头强制开启
+func WithOverride(ctx context.Context, name string, enabled bool) context.Context {
+	overrides := map[string]bool{name: enabled}
+	if parent, ok := ctx.Value(overrideKey{}).(map[string]bool); ok {
+		for k, v := range parent {
+			if k != name {
+				overrides[k] = v
+			}
+		}
+	}
+	return context.WithValue(ctx, overrideKey{}, overrides)
+}
+
+func override(ctx context.Context, name string) (enabled, ok bool) {
+	if ctx == nil {
+		return false, false
+	}
+	overrides, _ := ctx.Value(overrideKey{}).(map[string]bool)
+	enabled, ok = overrides[name]
+	return enabled, ok
+}
+
+var std = NewSet(nil)
+
+func Default() *Set { return std }
+
+func Update(flags map[string]Flag)                       { std.Update(flags) }
+func Enabled(ctx context.Context, name, key string) bool { return std.Enabled(ctx, name, key) }
+func Stats() map[string]Counts                           { return std.Stats() }
@@ -0,0 +1,117 @@
+package flags
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jianghushinian/gokit/config/config"
+)
+
+func TestFlagEvaluate(t *testing.T) {
+	enabled, disabled := true, false
+	half := 50.0
+	tests := []struct {
+		name string
+		flag Flag
+		key  string
+		want bool
+	}{
+		{name: "enabled", flag: Bool(true), key: "u1", want: true},
+		{name: "disabled", flag: Bool(false), key: "u1", want: false},
+		{name: "empty", flag: Flag{}, key: "u1", want: false},
+		{name: "allow", flag: Flag{Allow: []string{"u1"}}, key: "u1", want: true},
+		{name: "not allow", flag: Flag{Allow: []string{"u1"}}, key: "u2", want: false},
+		{name: "deny", flag: Flag{Enabled: &enabled, Deny: []string{"u1"}}, key: "u1", want: false},
+		{name: "kill switch", flag: Flag{Enabled: &disabled, Allow: []string{"u1"}}, key: "u1", want: false},
+		{name: "full rollout", flag: Percentage(100), key: "u1", want: true},
+		{name: "zero rollout", flag: Percentage(0), key: "u1", want: false},
+		{name: "allow before rollout", flag: Flag{Percentage: &half, Allow: []string{"u1"}}, key: "u1", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.flag.evaluate("new-checkout", tt.key))
+		})
+	}
+}
+
+func TestPercentageRollout(t *testing.T) {
+	f := Percentage(20)
+	n := 0
+	for i := 0; i < 10000; i++ {
+		key := "user-" + strconv.Itoa(i)
+		got := f.evaluate("new-checkout", key)
+		// 相同的 key 总是得到相同的结果
+		assert.Equal(t, got, f.evaluate("new-checkout", key))
+		if got {
+			n++
+		}
+	}
+	assert.InDelta(t, 2000, n, 200)
+}
+
+func TestSet(t *testing.T) {
+	s := NewSet(map[string]Flag{
+		"new-checkout": {Allow: []string{"u1"}},
+	})
+	ctx := context.Background()
+	assert.True(t, s.Enabled(ctx, "new-checkout", "u1"))
+	assert.False(t, s.Enabled(ctx, "new-checkout", "u2"))
+	assert.False(t, s.Enabled(ctx, "unknown", "u1"))
+
+	ctx = WithOverride(ctx, "unknown", true)
+	assert.True(t, s.Enabled(ctx, "unknown", "u1"))
+	assert.False(t, s.Enabled(WithOverride(ctx, "new-checkout", false), "new-checkout", "u1"))
+	assert.False(t, s.Enabled(ctx, "typo", "u1"))
+
+	// 未定义的功能开关合并计数
+	assert.Equal(t, map[string]Counts{
+		"new-checkout": {True: 1, False: 2},
+		UnknownFlag:    {True: 1, False: 2},
+	}, s.Stats())
+
+	s.Update(map[string]Flag{"dark-mode": Bool(true)})
+	assert.Equal(t, []string{"dark-mode"}, s.Names())
+	assert.True(t, s.Enabled(context.Background(), "dark-mode", ""))
+}
+
+type Config struct {
+	Flags map[string]Flag
+}
+
+func TestBind(t *testing.T) {
+	docs := map[config.FileType]string{
+		config.FileTypeYAML: "flags:\n  new-checkout:\n    allow: [u1]\n  dark-mode: true\n",
+		config.FileTypeJSON: `{"flags": {"new-checkout": {"allow": ["u1"]}, "dark-mode": true}}`,
+		config.FileTypeTOML: "[flags]\ndark-mode = true\n\n[flags.new-checkout]\nallow = [\"u1\"]\n",
+	}
+	for typ, doc := range docs {
+		t.Run(typ.String(), func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "config."+typ.String())
+			require.NoError(t, os.WriteFile(filename, []byte(doc), 0644))
+
+			store, err := config.NewStore[Config](config.WithFile(filename))
+			require.NoError(t, err)
+			s := NewSet(nil)
+			Bind(s, store, func(cfg *Config) map[string]Flag { return cfg.Flags })
+
+			ctx := context.Background()
+			assert.True(t, s.Enabled(ctx, "new-checkout", "u1"))
+			assert.False(t, s.Enabled(ctx, "new-checkout", "u2"))
+			assert.True(t, s.Enabled(ctx, "dark-mode", "u2"))
+
+			// 配置热加载后更新功能开关
+			require.NoError(t, config.DumpConfig(filename, map[string]interface{}{
+				"flags": map[string]interface{}{"new-checkout": map[string]interface{}{"percentage": 100}},
+			}, typ))
+			require.NoError(t, store.Reload())
+			assert.True(t, s.Enabled(ctx, "new-checkout", "u2"))
+			assert.False(t, s.Enabled(ctx, "dark-mode", "u2"))
+		})
+	}
+}
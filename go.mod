@@ -3,6 +3,7 @@ module github.com/jianghushinian/gokit
 go 1.20
 
 require (
+	github.com/BurntSushi/toml v1.5.0
 	github.com/lestrrat-go/file-rotatelogs v2.4.0+incompatible
 	github.com/stretchr/testify v1.8.0
 	go.uber.org/zap v1.24.0
@@ -0,0 +1,319 @@
+package zap
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// OverflowPolicy 异步写入缓冲区已满时的处理策略
+type OverflowPolicy int
+
+const (
+	// OverflowBlock 阻塞等待缓冲区有空闲位置，不会丢弃日志
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropDebugInfo 优先丢弃 Debug、Info 级别的日志：新日志为 Debug、Info 级别时直接丢弃，
+	// 否则丢弃缓冲区中最早的 Debug、Info 级别日志，缓冲区中全部为 Warn 及以上级别的日志时阻塞等待
+	OverflowDropDebugInfo
+	// OverflowDropNewest 丢弃新写入的日志
+	OverflowDropNewest
+)
+
+func (p OverflowPolicy) String() string {
+	switch p {
+	case OverflowBlock:
+		return "block"
+	case OverflowDropDebugInfo:
+		return "dropDebugInfo"
+	case OverflowDropNewest:
+		return "dropNewest"
+	}
+	return fmt.Sprintf("OverflowPolicy(%d)", int(p))
+}
+
+func (p OverflowPolicy) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+func (p *OverflowPolicy) UnmarshalText(text []byte) error {
+	for _, v := range []OverflowPolicy{OverflowBlock, OverflowDropDebugInfo, OverflowDropNewest} {
+		if string(text) == v.String() {
+			*p = v
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported overflow policy: %s", text)
+}
+
+// AsyncConfig 异步写入配置
+type AsyncConfig struct {
+	BufferSize    int            `yaml:"bufferSize" json:"bufferSize" toml:"bufferSize"`          // 缓冲区可以容纳的日志条数，默认 1024
+	FlushInterval ConfigDuration `yaml:"flushInterval" json:"flushInterval" toml:"flushInterval"` // 写入底层 io.Writer 的最长间隔，默认 1s
+	Overflow      OverflowPolicy `yaml:"overflow" json:"overflow" toml:"overflow"`                // 缓冲区已满时的处理策略，默认阻塞
+}
+
+// AsyncWriter 异步写入日志的 zapcore.WriteSyncer，日志先写入缓冲区，由后台 goroutine 批量写入底层 io.Writer
+// 通过 New、NewTee 或 LogConfig.Build 使用时可以获取日志级别，以便按照 OverflowDropDebugInfo 策略丢弃日志
+type AsyncWriter struct {
+	out io.Writer
+	buf *bufio.Writer
+	cfg AsyncConfig
+
+	mu      sync.Mutex
+	notFull *sync.Cond
+	queue   []asyncEntry
+	closed  bool
+
+	notify  chan struct{}
+	syncReq chan chan error
+	done    chan struct{}
+	stopped chan struct{}
+
+	dropped [FatalLevel - DebugLevel + 1]atomic.Uint64
+}
+
+type asyncEntry struct {
+	level Level
+	data  []byte
+}
+
+// ErrAsyncWriterClosed 写入已经关闭的 AsyncWriter
+var ErrAsyncWriterClosed = errors.New("async log writer is closed")
+
+// NewAsyncWriter 创建异步写入 out 的 AsyncWriter，使用完毕后需要调用 Close 写入缓冲区中剩余的日志
+func NewAsyncWriter(out io.Writer, cfg AsyncConfig) *AsyncWriter {
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = 1024
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = ConfigDuration(time.Second)
+	}
+	w := &AsyncWriter{
+		out:     out,
+		buf:     bufio.NewWriterSize(out, 256*1024),
+		cfg:     cfg,
+		queue:   make([]asyncEntry, 0, cfg.BufferSize),
+		notify:  make(chan struct{}, 1),
+		syncReq: make(chan chan error),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	w.notFull = sync.NewCond(&w.mu)
+	go w.run()
+	return w
+}
+
+// Write 写入一条日志，无法获取日志级别，按照 Info 级别处理
+func (w *AsyncWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(InfoLevel, p)
+}
+
+// WriteLevel 写入一条指定级别的日志
+func (w *AsyncWriter) WriteLevel(level Level, p []byte) (int, error) {
+	e := asyncEntry{level: level, data: append([]byte(nil), p...)}
+
+	w.mu.Lock()
+	for !w.closed && len(w.queue) >= w.cfg.BufferSize {
+		if w.overflow(e) {
+			w.mu.Unlock()
+			return len(p), nil
+		}
+	}
+	if w.closed {
+		w.mu.Unlock()
+		return 0, ErrAsyncWriterClosed
+	}
+	w.queue = append(w.queue, e)
+	w.mu.Unlock()
+
+	select {
+	case w.notify <- struct{}{}:
+	default:
+	}
+	return len(p), nil
+}
+
+// overflow 按照策略处理缓冲区已满的情况，返回 true 表示丢弃新日志，调用时需要持有锁
+func (w *AsyncWriter) overflow(e asyncEntry) (dropped bool) {
+	switch w.cfg.Overflow {
+	case OverflowDropNewest:
+		w.drop(e.level)
+		return true
+	case OverflowDropDebugInfo:
+		if e.level <= InfoLevel {
+			w.drop(e.level)
+			return true
+		}
+		for i, old := range w.queue {
+			if old.level <= InfoLevel {
+				w.drop(old.level)
+				w.queue = append(w.queue[:i], w.queue[i+1:]...)
+				return false
+			}
+		}
+	}
+	w.notFull.Wait()
+	return false
+}
+
+func (w *AsyncWriter) drop(level Level) {
+	if level < DebugLevel {
+		level = DebugLevel
+	}
+	if level > FatalLevel {
+		level = FatalLevel
+	}
+	w.dropped[level-DebugLevel].Add(1)
+}
+
+// Dropped 返回缓冲区已满时被丢弃的各级别日志条数
+func (w *AsyncWriter) Dropped() map[Level]uint64 {
+	dropped := make(map[Level]uint64)
+	for i := range w.dropped {
+		if n := w.dropped[i].Load(); n > 0 {
+			dropped[DebugLevel+Level(i)] = n
+		}
+	}
+	return dropped
+}
+
+// Buffered 返回缓冲区中等待写入的日志条数
+func (w *AsyncWriter) Buffered() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.queue)
+}
+
+// Sync 将缓冲区中的日志全部写入底层 io.Writer，底层 io.Writer 实现了 Sync 方法时同样会调用
+func (w *AsyncWriter) Sync() error {
+	ch := make(chan error, 1)
+	select {
+	case w.syncReq <- ch:
+		return <-ch
+	case <-w.stopped:
+		return nil
+	}
+}
+
+// Close 写入缓冲区中剩余的日志并停止后台 goroutine，之后的写入会返回 ErrAsyncWriterClosed
+func (w *AsyncWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	w.notFull.Broadcast()
+	w.mu.Unlock()
+
+	close(w.done)
+	<-w.stopped
+	return w.syncOut()
+}
+
+func (w *AsyncWriter) run() {
+	defer close(w.stopped)
+	ticker := time.NewTicker(time.Duration(w.cfg.FlushInterval))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.notify:
+			w.drain()
+		case <-ticker.C:
+			w.drain()
+			_ = w.buf.Flush()
+		case ch := <-w.syncReq:
+			w.drain()
+			ch <- w.syncOut()
+		case <-w.done:
+			w.drain()
+			return
+		}
+	}
+}
+
+// drain 将缓冲区中的日志写入 bufio.Writer
+func (w *AsyncWriter) drain() {
+	w.mu.Lock()
+	queue := w.queue
+	w.queue = make([]asyncEntry, 0, w.cfg.BufferSize)
+	w.notFull.Broadcast()
+	w.mu.Unlock()
+
+	for _, e := range queue {
+		_, _ = w.buf.Write(e.data)
+	}
+}
+
+func (w *AsyncWriter) syncOut() error {
+	if err := w.buf.Flush(); err != nil {
+		return err
+	}
+	if s, ok := w.out.(interface{ Sync() error }); ok {
+		return s.Sync()
+	}
+	return nil
+}
+
+// levelWriter 可以获取日志级别的 io.Writer
+type levelWriter interface {
+	WriteLevel(level Level, p []byte) (int, error)
+	Sync() error
+}
+
+// newCore 创建写入 out 的 zapcore.Core，out 为 levelWriter 时写入日志会带上日志级别
+func newCore(enc zapcore.Encoder, out io.Writer, enabler zapcore.LevelEnabler) zapcore.Core {
+	if lw, ok := out.(levelWriter); ok {
+		return &levelWriterCore{LevelEnabler: enabler, enc: enc, out: lw}
+	}
+	return zapcore.NewCore(enc, zapcore.AddSync(out), enabler)
+}
+
+// levelWriterCore 与 zapcore.NewCore 创建的 Core 相同，只是写入时带上日志级别
+type levelWriterCore struct {
+	zapcore.LevelEnabler
+	enc zapcore.Encoder
+	out levelWriter
+}
+
+func (c *levelWriterCore) With(fields []Field) zapcore.Core {
+	clone := &levelWriterCore{LevelEnabler: c.LevelEnabler, enc: c.enc.Clone(), out: c.out}
+	for i := range fields {
+		fields[i].AddTo(clone.enc)
+	}
+	return clone
+}
+
+func (c *levelWriterCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *levelWriterCore) Write(ent zapcore.Entry, fields []Field) error {
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	_, err = c.out.WriteLevel(ent.Level, buf.Bytes())
+	buf.Free()
+	if err != nil {
+		return err
+	}
+	if ent.Level > ErrorLevel {
+		// Panic、Fatal 级别的日志写入后进程可能退出，需要立即写入
+		_ = c.Sync()
+	}
+	return nil
+}
+
+func (c *levelWriterCore) Sync() error {
+	return c.out.Sync()
+}
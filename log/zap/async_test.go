@@ -0,0 +1,190 @@
+package zap
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syncBuffer 可以并发写入以及读取的 bytes.Buffer
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// blockingWriter 在 release 之前阻塞写入，用于模拟缓慢的底层 io.Writer
+type blockingWriter struct {
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	entered chan struct{}
+	release chan struct{}
+	once    sync.Once
+	syncs   int
+}
+
+func newBlockingWriter() *blockingWriter {
+	return &blockingWriter{entered: make(chan struct{}), release: make(chan struct{})}
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	w.once.Do(func() { close(w.entered) })
+	<-w.release
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *blockingWriter) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.syncs++
+	return nil
+}
+
+func (w *blockingWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+// stall 写入一条日志并使后台 goroutine 阻塞在底层 io.Writer 上，之后写入的日志都会留在缓冲区中
+func stall(t *testing.T, l *Logger, out *blockingWriter) <-chan error {
+	t.Helper()
+	l.Warn("first")
+	errc := make(chan error, 1)
+	go func() { errc <- l.Sync() }()
+	select {
+	case <-out.entered:
+	case <-time.After(time.Second):
+		t.Fatal("async writer did not write to the underlying writer")
+	}
+	return errc
+}
+
+func TestAsyncWriterSync(t *testing.T) {
+	var out syncBuffer
+	w := NewAsyncWriter(&out, AsyncConfig{FlushInterval: ConfigDuration(time.Hour)})
+	defer w.Close()
+	l := New(w, DebugLevel, WithKeyNames(KeyNames{Time: "-"}))
+
+	for i := 0; i < 100; i++ {
+		l.Info("msg")
+	}
+	// 缓冲区中的日志在 Sync 之后全部写入
+	require.NoError(t, l.Sync())
+	assert.Equal(t, 100, strings.Count(out.String(), `{"level":"info","msg":"msg"}`+"\n"))
+	assert.Zero(t, w.Buffered())
+	assert.Empty(t, w.Dropped())
+}
+
+func TestAsyncWriterFlushInterval(t *testing.T) {
+	var out syncBuffer
+	w := NewAsyncWriter(&out, AsyncConfig{FlushInterval: ConfigDuration(10 * time.Millisecond)})
+	defer w.Close()
+	l := New(w, DebugLevel, WithKeyNames(KeyNames{Time: "-"}))
+
+	l.Info("msg")
+	assert.Eventually(t, func() bool { return out.String() == `{"level":"info","msg":"msg"}`+"\n" },
+		time.Second, 5*time.Millisecond)
+}
+
+func TestAsyncWriterOverflow(t *testing.T) {
+	tests := []struct {
+		policy  OverflowPolicy
+		want    []string
+		dropped map[Level]uint64
+	}{
+		{
+			policy:  OverflowDropNewest,
+			want:    []string{"first", "info 1", "warn 2"},
+			dropped: map[Level]uint64{InfoLevel: 1, ErrorLevel: 1},
+		},
+		{
+			// Error 级别的日志挤掉了缓冲区中最早的 Info 级别日志
+			policy:  OverflowDropDebugInfo,
+			want:    []string{"first", "warn 2", "error 4"},
+			dropped: map[Level]uint64{InfoLevel: 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			out := newBlockingWriter()
+			w := NewAsyncWriter(out, AsyncConfig{BufferSize: 2, FlushInterval: ConfigDuration(time.Hour), Overflow: tt.policy})
+			l := New(w, DebugLevel, WithKeyNames(KeyNames{Time: "-", Level: "-"}))
+
+			errc := stall(t, l, out)
+			l.Info("info 1")
+			l.Warn("warn 2")
+			l.Info("info 3")
+			l.Error("error 4")
+			assert.Equal(t, tt.dropped, w.Dropped())
+
+			close(out.release)
+			require.NoError(t, <-errc)
+			require.NoError(t, w.Close())
+
+			var want string
+			for _, msg := range tt.want {
+				want += `{"msg":"` + msg + `"}` + "\n"
+			}
+			assert.Equal(t, want, out.String())
+		})
+	}
+}
+
+func TestAsyncWriterOverflowBlock(t *testing.T) {
+	out := newBlockingWriter()
+	w := NewAsyncWriter(out, AsyncConfig{BufferSize: 1, FlushInterval: ConfigDuration(time.Hour)})
+	l := New(w, DebugLevel, WithKeyNames(KeyNames{Time: "-", Level: "-"}))
+
+	errc := stall(t, l, out)
+	l.Info("second")
+	written := make(chan struct{})
+	go func() {
+		l.Debug("third")
+		close(written)
+	}()
+	select {
+	case <-written:
+		t.Fatal("write should block while the buffer is full")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(out.release)
+	require.NoError(t, <-errc)
+	<-written
+	require.NoError(t, w.Close())
+	assert.Equal(t, `{"msg":"first"}
+{"msg":"second"}
+{"msg":"third"}
+`, out.String())
+	assert.Empty(t, w.Dropped())
+	assert.Equal(t, 2, out.syncs)
+
+	_, err := w.Write([]byte("closed\n"))
+	assert.ErrorIs(t, err, ErrAsyncWriterClosed)
+}
+
+func TestOverflowPolicyText(t *testing.T) {
+	var p OverflowPolicy
+	require.NoError(t, p.UnmarshalText([]byte("dropDebugInfo")))
+	assert.Equal(t, OverflowDropDebugInfo, p)
+	assert.EqualError(t, p.UnmarshalText([]byte("drop")), "unsupported overflow policy: drop")
+}
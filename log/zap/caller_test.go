@@ -0,0 +1,82 @@
+package zap
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCaller(t *testing.T) {
+	var buf bytes.Buffer
+	opts := []Option{AddCaller(), WithKeyNames(KeyNames{Time: "-"})}
+	l := New(&buf, DebugLevel, opts...)
+	tee := NewTee([]TeeOption{{Out: &buf, LevelEnablerFunc: func(Level) bool { return true }}}, opts...)
+
+	old := Default()
+	defer ReplaceDefault(old)
+	ReplaceDefault(New(&buf, DebugLevel, opts...))
+	ctx := ContextWithRequestID(context.Background(), "req-1")
+
+	tests := []struct {
+		name string
+		fn   func()
+	}{
+		{name: "method", fn: func() { l.Info("msg") }},
+		{name: "sugared method", fn: func() { l.Infof("msg %d", 1) }},
+		{name: "keys and values method", fn: func() { l.Infow("msg", "k", "v") }},
+		{name: "child", fn: func() { l.Named("child").With(Int("k", 1)).Warn("msg") }},
+		{name: "with options", fn: func() { l.WithOptions(Fields(Int("k", 1))).Error("msg") }},
+		{name: "tee", fn: func() { tee.Info("msg") }},
+		{name: "package", fn: func() { Info("msg") }},
+		{name: "package sugared", fn: func() { Warnf("msg %d", 1) }},
+		{name: "package keys and values", fn: func() { Errorw("msg", "k", "v") }},
+		{name: "package child", fn: func() { Named("child").Debug("msg") }},
+		{name: "context method", fn: func() { l.InfoContext(ctx, "msg") }},
+		{name: "context logger", fn: func() { l.Ctx(ctx).Info("msg") }},
+		{name: "package context", fn: func() { WarnContext(ctx, "msg") }},
+		{name: "package with context", fn: func() { WithContext(ctx).Error("msg") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			tt.fn()
+			_, line := runtime.FuncForPC(reflect.ValueOf(tt.fn).Pointer()).FileLine(reflect.ValueOf(tt.fn).Pointer())
+			assert.Contains(t, buf.String(), fmt.Sprintf(`"caller":"zap/caller_test.go:%d"`, line))
+		})
+	}
+}
+
+func TestCallerWithCallerSkip(t *testing.T) {
+	var buf bytes.Buffer
+	// 之前版本的用法：自行传入 AddCallerSkip(1) 跳过 Logger 方法这一层调用
+	opts := []Option{AddCaller(), AddCallerSkip(1), WithKeyNames(KeyNames{Time: "-"})}
+	l := New(&buf, DebugLevel, opts...)
+	tee := NewTee([]TeeOption{{Out: &buf, LevelEnablerFunc: func(Level) bool { return true }}}, opts...)
+	// 自行封装的函数需要再跳过一层调用
+	wrapped := New(&buf, DebugLevel, AddCaller(), AddCallerSkip(2), WithKeyNames(KeyNames{Time: "-"}))
+	logf := func(msg string) { wrapped.Info(msg) }
+
+	tests := []struct {
+		name string
+		fn   func()
+	}{
+		{name: "method", fn: func() { l.Info("msg") }},
+		{name: "sugared method", fn: func() { l.Infof("msg %d", 1) }},
+		{name: "child", fn: func() { l.Named("child").Warn("msg") }},
+		{name: "tee", fn: func() { tee.Info("msg") }},
+		{name: "wrapped", fn: func() { logf("msg") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			tt.fn()
+			_, line := runtime.FuncForPC(reflect.ValueOf(tt.fn).Pointer()).FileLine(reflect.ValueOf(tt.fn).Pointer())
+			assert.Contains(t, buf.String(), fmt.Sprintf(`"caller":"zap/caller_test.go:%d"`, line))
+		})
+	}
+}
@@ -0,0 +1,309 @@
+package zap
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// LogConfig 日志配置，可以作为配置文件的一部分通过 config 包加载：
+//
+//	log:
+//	  level: info
+//	  loggers:
+//	    db.*: debug
+//	  encoding: json
+//	  caller: true
+//	  stacktraceLevel: error
+//	  dedupe: 10s
+//	  outputs:
+//	    - path: stdout
+//	      maxLevel: info
+//	    - path: /var/log/app/error.log
+//	      minLevel: warn
+//	      rotate: size
+//	      rotation:
+//	        maxSize: 100
+type LogConfig struct {
+	Level           Level            `yaml:"level" json:"level" toml:"level"`                               // 日志级别，默认 info
+	Loggers         map[string]Level `yaml:"loggers" json:"loggers" toml:"loggers"`                         // 按名称或者通配符模式为子 Logger 单独设置的日志级别，如 db.*: debug
+	Encoding        string           `yaml:"encoding" json:"encoding" toml:"encoding"`                      // 编码格式：json（默认）、console
+	Outputs         []OutputConfig   `yaml:"outputs" json:"outputs" toml:"outputs"`                         // 日志输出，默认输出到 stderr
+	Caller          bool             `yaml:"caller" json:"caller" toml:"caller"`                            // 是否记录日志调用位置
+	StacktraceLevel *Level           `yaml:"stacktraceLevel" json:"stacktraceLevel" toml:"stacktraceLevel"` // 记录调用栈的最低级别，默认不记录
+	Sampling        *SamplingConfig  `yaml:"sampling,omitempty" json:"sampling,omitempty" toml:"sampling"`  // 日志采样，默认不采样
+	Dedupe          ConfigDuration   `yaml:"dedupe" json:"dedupe" toml:"dedupe"`                            // 合并该时间窗口内重复的日志，默认不合并
+}
+
+// OutputConfig 日志输出配置
+type OutputConfig struct {
+	Name     string          `yaml:"name" json:"name" toml:"name"`                                 // 输出名称，用于在运行时修改输出的日志级别范围，默认为 Path
+	Path     string          `yaml:"path" json:"path" toml:"path"`                                 // stdout、stderr 或文件路径
+	MinLevel *Level          `yaml:"minLevel" json:"minLevel" toml:"minLevel"`                     // 输出的最低级别，默认不限制
+	MaxLevel *Level          `yaml:"maxLevel" json:"maxLevel" toml:"maxLevel"`                     // 输出的最高级别，默认不限制
+	Rotate   string          `yaml:"rotate" json:"rotate" toml:"rotate"`                           // 日志轮转方式：size、time，默认不轮转
+	Async    *AsyncConfig    `yaml:"async,omitempty" json:"async,omitempty" toml:"async"`          // 异步写入配置，默认同步写入
+	Rotation *RotationConfig `yaml:"rotation,omitempty" json:"rotation,omitempty" toml:"rotation"` // 日志轮转配置，没有设置的配置使用 NewProductionRotateConfig 的默认值
+}
+
+// RotationConfig 日志输出的轮转配置，文件名固定为 OutputConfig.Path
+type RotationConfig struct {
+	MaxAge       int            `yaml:"maxAge" json:"maxAge" toml:"maxAge"`                   // 保留旧日志文件的最大天数，默认 30
+	RotationTime ConfigDuration `yaml:"rotationTime" json:"rotationTime" toml:"rotationTime"` // 按时间轮转的间隔，默认 24h
+	MaxSize      int            `yaml:"maxSize" json:"maxSize" toml:"maxSize"`                // 按大小轮转时日志文件最大大小（MB），默认 100
+	MaxBackups   int            `yaml:"maxBackups" json:"maxBackups" toml:"maxBackups"`       // 按大小轮转时保留日志文件的最大数量，默认 100
+	Compress     *bool          `yaml:"compress" json:"compress" toml:"compress"`             // 按大小轮转时是否压缩旧日志文件，默认 true
+	LocalTime    bool           `yaml:"localTime" json:"localTime" toml:"localTime"`          // 是否使用本地时间，默认 UTC 时间
+}
+
+// SamplingConfig 日志采样配置，每个 Tick 内相同级别、相同内容的日志
+// 只记录前 Initial 条，之后每 Thereafter 条记录一条
+type SamplingConfig struct {
+	Tick       ConfigDuration `yaml:"tick" json:"tick" toml:"tick"` // 默认 1s
+	Initial    int            `yaml:"initial" json:"initial" toml:"initial"`
+	Thereafter int            `yaml:"thereafter" json:"thereafter" toml:"thereafter"`
+}
+
+func (s *SamplingConfig) tick() time.Duration {
+	if s.Tick <= 0 {
+		return time.Second
+	}
+	return time.Duration(s.Tick)
+}
+
+// ConfigDuration 配置中的时间间隔，支持 time.ParseDuration 格式的字符串（如 10s、1h30m）以及表示纳秒数的整数，
+// 与 time.Duration 不同，JSON 配置中同样可以使用字符串
+type ConfigDuration time.Duration
+
+func (d ConfigDuration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func (d *ConfigDuration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		n, nerr := strconv.ParseInt(string(text), 10, 64)
+		if nerr != nil {
+			return fmt.Errorf("invalid duration: %q", text)
+		}
+		v = time.Duration(n)
+	}
+	*d = ConfigDuration(v)
+	return nil
+}
+
+func (d *ConfigDuration) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	if s, err := strconv.Unquote(string(data)); err == nil {
+		data = []byte(s)
+	}
+	return d.UnmarshalText(data)
+}
+
+// Validate 校验日志配置
+func (c *LogConfig) Validate() error {
+	switch c.Encoding {
+	case "", "json", "console":
+	default:
+		return fmt.Errorf("unsupported log encoding: %s", c.Encoding)
+	}
+	names := make(map[string]struct{}, len(c.Outputs))
+	for i, out := range c.Outputs {
+		if out.Path == "" {
+			return fmt.Errorf("log output %d: path is required", i)
+		}
+		// 输出名称用于在运行时修改输出的日志级别范围，不能重复
+		if _, ok := names[out.name()]; ok {
+			return fmt.Errorf("log output %s: duplicate name", out.name())
+		}
+		names[out.name()] = struct{}{}
+		if err := out.levels().validate(); err != nil {
+			return fmt.Errorf("log output %s: %w", out.name(), err)
+		}
+		switch out.Rotate {
+		case "":
+		case "size", "time":
+			if out.Path == "stdout" || out.Path == "stderr" {
+				return fmt.Errorf("log output %s: can not rotate", out.Path)
+			}
+		default:
+			return fmt.Errorf("log output %s: unsupported rotate: %s", out.Path, out.Rotate)
+		}
+	}
+	for name := range c.Loggers {
+		if err := validatePattern(name); err != nil {
+			return err
+		}
+	}
+	if c.Sampling != nil && c.Sampling.Thereafter < 0 {
+		return errors.New("log sampling: thereafter must not be negative")
+	}
+	return nil
+}
+
+// Build 根据日志配置创建 Logger，opts 会附加在配置生成的选项之后，同样支持编码器选项
+// 所有输出共用同一个 AtomicLevel，因此 SetLevel 以及 ApplyConfig 对所有输出生效
+// Build 打开的文件以及异步写入的后台 goroutine 需要在不再使用 Logger 时（如重新 Build 之后）通过 Logger.Close 关闭
+func (c *LogConfig) Build(opts ...Option) (*Logger, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	al := zap.NewAtomicLevelAt(c.Level)
+	if c.Encoding == "console" {
+		opts = append([]Option{WithConsoleEncoder()}, opts...)
+	}
+	if s := c.Sampling; s != nil {
+		opts = append([]Option{WithSampling(time.Duration(s.Tick), s.Initial, s.Thereafter)}, opts...)
+	}
+	if c.Dedupe > 0 {
+		opts = append([]Option{WithDedupe(time.Duration(c.Dedupe))}, opts...)
+	}
+	cfg, opts := splitOptions(opts)
+	outputs := c.Outputs
+	if len(outputs) == 0 {
+		outputs = []OutputConfig{{Path: "stderr"}}
+	}
+	cores := make([]zapcore.Core, 0, len(outputs))
+	levels := make(outputLevels)
+	var closers []io.Closer
+	for _, out := range outputs {
+		w, cs, err := out.writer()
+		closers = append(closers, cs...)
+		if err != nil {
+			_ = closeAll(closers)
+			return nil, fmt.Errorf("log output %s: %w", out.name(), err)
+		}
+		lr := out.levels()
+		cores = append(cores, cfg.outputCore(w, levels.add(out.name(), &lr)))
+	}
+	core := cfg.wrap(zapcore.NewTee(cores...))
+
+	var zopts []Option
+	if c.Caller {
+		zopts = append(zopts, zap.AddCaller())
+	}
+	if c.StacktraceLevel != nil {
+		zopts = append(zopts, zap.AddStacktrace(*c.StacktraceLevel))
+	}
+	reg := newLevelRegistry(&al)
+	reg.replace(c.Loggers)
+	l := newLogger(core, reg, append(zopts, opts...))
+	l.outputs = levels
+	l.closer = &closer{closers: closers}
+	return l, nil
+}
+
+// ApplyConfig 应用热加载后的日志配置，只会更新日志级别、按名称设置的日志级别以及已有输出的日志级别范围
+// 输出、编码格式等配置的变更需要重新调用 Build 创建 Logger
+func (l *Logger) ApplyConfig(c *LogConfig) {
+	l.SetLevel(c.Level)
+	l.levels.replace(c.Loggers)
+	for _, out := range c.Outputs {
+		_ = l.SetOutputLevels(out.name(), out.levels())
+	}
+}
+
+// WatchConfig 通过 onChange（如 config.Store 的 OnChange 方法）注册回调，在配置重新加载后通过 ApplyConfig
+// 将 get 返回的日志配置应用到 l，log/zap 不依赖 config 包，避免引入其注册的命令行参数：
+//
+//	log.WatchConfig(logger, store.OnChange, func(cfg *Config) *log.LogConfig { return &cfg.Log })
+func WatchConfig[T any](l *Logger, onChange func(fn func(old, new *T)), get func(cfg *T) *LogConfig) {
+	onChange(func(_, cfg *T) {
+		if c := get(cfg); c != nil {
+			l.ApplyConfig(c)
+		}
+	})
+}
+
+// writer 打开输出，返回需要在 Logger.Close 时关闭的 io.Closer
+func (o OutputConfig) writer() (io.Writer, []io.Closer, error) {
+	w, err := o.open()
+	if err != nil {
+		return nil, nil, err
+	}
+	var closers []io.Closer
+	if c, ok := w.(io.Closer); ok && w != os.Stdout && w != os.Stderr {
+		closers = append(closers, c)
+	}
+	if o.Async == nil {
+		return w, closers, nil
+	}
+	// 先关闭异步写入的 Writer，写入缓冲区中剩余的日志后再关闭文件
+	aw := NewAsyncWriter(w, *o.Async)
+	return aw, append([]io.Closer{aw}, closers...), nil
+}
+
+func (o OutputConfig) open() (io.Writer, error) {
+	switch o.Path {
+	case "stdout":
+		return os.Stdout, nil
+	case "stderr":
+		return os.Stderr, nil
+	}
+
+	if o.Rotate != "" {
+		cfg := o.rotateConfig()
+		if o.Rotate == "time" {
+			return newRotateByTime(cfg)
+		}
+		return NewRotateBySize(cfg), nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(o.Path), 0755); err != nil {
+		return nil, err
+	}
+	return os.OpenFile(o.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+}
+
+func (o OutputConfig) rotateConfig() *RotateConfig {
+	cfg := NewProductionRotateConfig(o.Path)
+	r := o.Rotation
+	if r == nil {
+		return cfg
+	}
+	if r.MaxAge > 0 {
+		cfg.MaxAge = r.MaxAge
+	}
+	if r.RotationTime > 0 {
+		cfg.RotationTime = time.Duration(r.RotationTime)
+	}
+	if r.MaxSize > 0 {
+		cfg.MaxSize = r.MaxSize
+	}
+	if r.MaxBackups > 0 {
+		cfg.MaxBackups = r.MaxBackups
+	}
+	if r.Compress != nil {
+		cfg.Compress = *r.Compress
+	}
+	cfg.LocalTime = r.LocalTime
+	return cfg
+}
+
+func (o OutputConfig) name() string {
+	if o.Name != "" {
+		return o.Name
+	}
+	return o.Path
+}
+
+func (o OutputConfig) levels() LevelRange {
+	lr := AllLevels
+	if o.MinLevel != nil {
+		lr.Min = *o.MinLevel
+	}
+	if o.MaxLevel != nil {
+		lr.Max = *o.MaxLevel
+	}
+	return lr
+}
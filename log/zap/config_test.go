@@ -0,0 +1,260 @@
+package zap
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jianghushinian/gokit/config/config"
+)
+
+func TestLogConfigBuild(t *testing.T) {
+	dir := t.TempDir()
+	info, warn := filepath.Join(dir, "info.log"), filepath.Join(dir, "warn.log")
+	doc := `log:
+  level: debug
+  caller: true
+  stacktraceLevel: error
+  dedupe: 10s
+  outputs:
+    - path: ` + info + `
+      maxLevel: info
+      async:
+        flushInterval: 1h
+        overflow: dropNewest
+    - path: ` + warn + `
+      minLevel: warn
+`
+	filename := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(filename, []byte(doc), 0644))
+
+	var cfg struct {
+		Log LogConfig
+	}
+	require.NoError(t, config.LoadConfig(filename, &cfg, config.FileTypeYAML))
+	assert.Equal(t, DebugLevel, cfg.Log.Level)
+	assert.Equal(t, ErrorLevel, *cfg.Log.StacktraceLevel)
+	assert.Equal(t, ConfigDuration(10*time.Second), cfg.Log.Dedupe)
+	assert.Equal(t, &AsyncConfig{FlushInterval: ConfigDuration(time.Hour), Overflow: OverflowDropNewest}, cfg.Log.Outputs[0].Async)
+
+	l, err := cfg.Log.Build()
+	require.NoError(t, err)
+	l.Debug("debug msg")
+	_, _, line, _ := runtime.Caller(0)
+	l.Warn("warn msg")
+
+	// 配置热加载后更新日志级别
+	cfg.Log.Level = WarnLevel
+	l.ApplyConfig(&cfg.Log)
+	l.Info("info msg")
+	l.Error("error msg")
+	require.NoError(t, l.Sync())
+
+	require.NoError(t, l.Close())
+
+	lines := readLines(t, info)
+	require.Len(t, lines, 1)
+	assert.Contains(t, lines[0], `"msg":"debug msg"`)
+	assert.Contains(t, lines[0], fmt.Sprintf(`"caller":"zap/config_test.go:%d"`, line-1))
+
+	lines = readLines(t, warn)
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"msg":"warn msg"`)
+	assert.NotContains(t, lines[0], `"stacktrace"`)
+	assert.Contains(t, lines[1], `"msg":"error msg"`)
+	assert.Contains(t, lines[1], `"stacktrace"`)
+}
+
+func TestWatchConfig(t *testing.T) {
+	type Config struct {
+		Log LogConfig
+	}
+	filename := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(filename, []byte("log:\n  level: info\n"), 0644))
+	store, err := config.NewStore[Config](config.WithFile(filename))
+	require.NoError(t, err)
+
+	var buf syncBuffer
+	l := New(&buf, store.Get().Log.Level, WithKeyNames(KeyNames{Time: "-"}))
+	WatchConfig(l, store.OnChange, func(cfg *Config) *LogConfig { return &cfg.Log })
+	l.Debug("before")
+
+	require.NoError(t, os.WriteFile(filename, []byte("log:\n  level: debug\n  loggers:\n    db: error\n"), 0644))
+	require.NoError(t, store.Reload())
+	assert.Equal(t, DebugLevel, l.Level())
+	l.Debug("after")
+	l.Named("db").Warn("db")
+	assert.Equal(t, `{"level":"debug","msg":"after"}
+`, buf.String())
+}
+
+func TestLogConfigDurations(t *testing.T) {
+	want := LogConfig{
+		Dedupe:   ConfigDuration(10 * time.Second),
+		Sampling: &SamplingConfig{Tick: ConfigDuration(2 * time.Second), Initial: 10},
+		Outputs: []OutputConfig{{
+			Path:     "app.log",
+			Rotate:   "time",
+			Rotation: &RotationConfig{RotationTime: ConfigDuration(time.Hour)},
+			Async:    &AsyncConfig{FlushInterval: ConfigDuration(500 * time.Millisecond)},
+		}},
+	}
+	docs := map[config.FileType]string{
+		config.FileTypeYAML: "log:\n  dedupe: 10s\n  sampling:\n    tick: 2s\n    initial: 10\n  outputs:\n    - path: app.log\n      rotate: time\n      rotation:\n        rotationTime: 1h\n      async:\n        flushInterval: 500ms\n",
+		config.FileTypeJSON: `{"log": {"dedupe": "10s", "sampling": {"tick": "2s", "initial": 10}, "outputs": [{"path": "app.log", "rotate": "time", "rotation": {"rotationTime": "1h"}, "async": {"flushInterval": "500ms"}}]}}`,
+		config.FileTypeTOML: "[log]\ndedupe = \"10s\"\n\n[log.sampling]\ntick = \"2s\"\ninitial = 10\n\n[[log.outputs]]\npath = \"app.log\"\nrotate = \"time\"\n\n[log.outputs.rotation]\nrotationTime = \"1h\"\n\n[log.outputs.async]\nflushInterval = \"500ms\"\n",
+	}
+	for typ, doc := range docs {
+		t.Run(typ.String(), func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "config")
+			require.NoError(t, os.WriteFile(filename, []byte(doc), 0644))
+			var cfg struct {
+				Log LogConfig
+			}
+			require.NoError(t, config.LoadConfig(filename, &cfg, typ))
+			assert.Equal(t, want, cfg.Log)
+		})
+	}
+
+	// 整数表示纳秒数
+	var d ConfigDuration
+	require.NoError(t, json.Unmarshal([]byte(`1000000000`), &d))
+	assert.Equal(t, ConfigDuration(time.Second), d)
+	assert.Error(t, json.Unmarshal([]byte(`"10x"`), &d))
+	data, err := json.Marshal(ConfigDuration(90 * time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, `"1m30s"`, string(data))
+}
+
+func TestLogConfigValidate(t *testing.T) {
+	warn, info := WarnLevel, InfoLevel
+	tests := []struct {
+		name string
+		cfg  LogConfig
+		err  string
+	}{
+		{name: "default", cfg: LogConfig{}},
+		{name: "encoding", cfg: LogConfig{Encoding: "xml"}, err: "unsupported log encoding: xml"},
+		{name: "path", cfg: LogConfig{Outputs: []OutputConfig{{}}}, err: "log output 0: path is required"},
+		{
+			name: "level range",
+			cfg:  LogConfig{Outputs: []OutputConfig{{Path: "stdout", MinLevel: &warn, MaxLevel: &info}}},
+			err:  "log output stdout: min level warn is greater than max level info",
+		},
+		{
+			name: "rotate stdout",
+			cfg:  LogConfig{Outputs: []OutputConfig{{Path: "stdout", Rotate: "size"}}},
+			err:  "log output stdout: can not rotate",
+		},
+		{
+			name: "rotate",
+			cfg:  LogConfig{Outputs: []OutputConfig{{Path: "app.log", Rotate: "day"}}},
+			err:  "log output app.log: unsupported rotate: day",
+		},
+		{
+			name: "duplicate name",
+			cfg:  LogConfig{Outputs: []OutputConfig{{Path: "stdout"}, {Path: "stderr", Name: "stdout"}}},
+			err:  "log output stdout: duplicate name",
+		},
+		{
+			name: "duplicate path",
+			cfg:  LogConfig{Outputs: []OutputConfig{{Path: "app.log"}, {Path: "app.log", MinLevel: &warn}}},
+			err:  "log output app.log: duplicate name",
+		},
+		{
+			name: "named",
+			cfg:  LogConfig{Outputs: []OutputConfig{{Path: "app.log"}, {Path: "app.log", Name: "error", MinLevel: &warn}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestLogConfigClose(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "app.log")
+	cfg := LogConfig{Outputs: []OutputConfig{{Path: filename, Async: &AsyncConfig{FlushInterval: ConfigDuration(time.Hour)}}}}
+	l, err := cfg.Build()
+	require.NoError(t, err)
+	aw := l.closer.closers[0].(*AsyncWriter)
+
+	// Close 写入缓冲区中剩余的日志，不需要先调用 Sync
+	l.Named("child").Info("buffered")
+	require.NoError(t, l.Named("child").Close())
+	assert.Contains(t, readLines(t, filename)[0], `"msg":"buffered"`)
+	_, err = aw.Write([]byte("closed\n"))
+	assert.ErrorIs(t, err, ErrAsyncWriterClosed)
+	require.NoError(t, l.Close())
+
+	// 同步写入的文件同样只会关闭一次
+	cfg.Outputs[0].Async = nil
+	l, err = cfg.Build()
+	require.NoError(t, err)
+	require.NoError(t, l.Close())
+	require.NoError(t, l.Close())
+}
+
+func TestLogConfigRotate(t *testing.T) {
+	// 目录名中的 "." 不影响轮转文件名
+	dir := filepath.Join(t.TempDir(), "logs.d")
+	cfg := LogConfig{Outputs: []OutputConfig{
+		{Path: filepath.Join(dir, "app.log"), Rotate: "time"},
+		{Path: filepath.Join(dir, "access"), Rotate: "time"},
+		{Path: filepath.Join(dir, "size.log"), Rotate: "size", Rotation: &RotationConfig{MaxSize: 10}},
+	}}
+	l, err := cfg.Build()
+	require.NoError(t, err)
+	l.Info("rotate")
+	require.NoError(t, l.Close())
+	require.NoError(t, l.Close())
+
+	for _, pattern := range []string{`app\.\d{4}-\d{2}-\d{2}-\d{2}-\d{2}-\d{2}\.log`, `access\.\d{4}-\d{2}-\d{2}-\d{2}-\d{2}-\d{2}`, `size\.log`} {
+		matches, err := filepath.Glob(filepath.Join(dir, "*"))
+		require.NoError(t, err)
+		var found bool
+		for _, m := range matches {
+			if ok, _ := regexp.MatchString(`^`+pattern+`$`, filepath.Base(m)); ok {
+				found = true
+				assert.Contains(t, readLines(t, m)[0], `"msg":"rotate"`)
+			}
+		}
+		assert.True(t, found, "%s not found in %v", pattern, matches)
+	}
+
+	// 文件名不合法时返回错误
+	cfg = LogConfig{Outputs: []OutputConfig{{Path: filepath.Join(dir, "app-%Q.log"), Rotate: "time"}}}
+	_, err = cfg.Build()
+	assert.Error(t, err)
+}
+
+func TestOutputRotateConfig(t *testing.T) {
+	off := false
+	assert.True(t, OutputConfig{Path: "app.log"}.rotateConfig().Compress)
+	// 设置了其他轮转配置时 compress 默认仍为 true
+	cfg := OutputConfig{Path: "app.log", Rotation: &RotationConfig{MaxSize: 10, LocalTime: true}}.rotateConfig()
+	assert.Equal(t, &RotateConfig{Filename: "app.log", MaxAge: 30, RotationTime: 24 * time.Hour, MaxSize: 10, MaxBackups: 100, Compress: true, LocalTime: true}, cfg)
+	assert.False(t, OutputConfig{Path: "app.log", Rotation: &RotationConfig{Compress: &off}}.rotateConfig().Compress)
+}
+
+func readLines(t *testing.T, filename string) []string {
+	t.Helper()
+	data, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
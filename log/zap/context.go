@@ -0,0 +1,225 @@
+package zap
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// 从 context 中提取的字段的键名
+const (
+	RequestIDKey = "requestID"
+	TraceIDKey   = "traceID"
+	SpanIDKey    = "spanID"
+	UserIDKey    = "userID"
+)
+
+type (
+	loggerKey    struct{}
+	fieldsKey    struct{}
+	requestIDKey struct{}
+	traceKey     struct{}
+	userIDKey    struct{}
+)
+
+type trace struct {
+	traceID, spanID string
+}
+
+// NewContext 返回携带 Logger 的 context，之后可以通过 FromContext、WithContext 取出
+func NewContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// FromContext 返回 context 中携带的 Logger，没有时（包括 ctx 为 nil）返回默认 Logger
+func FromContext(ctx context.Context) *Logger {
+	if ctx == nil {
+		return std
+	}
+	if l, ok := ctx.Value(loggerKey{}).(*Logger); ok {
+		return l
+	}
+	return std
+}
+
+// ContextWithFields 返回附加了字段的 context，字段会追加在 context 中已有的字段之后
+func ContextWithFields(ctx context.Context, fields ...Field) context.Context {
+	if len(fields) == 0 {
+		return ctx
+	}
+	old, _ := ctx.Value(fieldsKey{}).([]Field)
+	return context.WithValue(ctx, fieldsKey{}, append(old[:len(old):len(old)], fields...))
+}
+
+// ContextWithRequestID 返回携带请求 ID 的 context
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// MaxRequestIDLength 请求 ID 的最大长度
+const MaxRequestIDLength = 128
+
+// ValidRequestID 返回从请求头等外部输入读取的请求 ID 是否有效：不为空、不超过 MaxRequestIDLength 个字符，
+// 并且只包含字母、数字以及 - _ . : / + = 等字符，可以覆盖 UUID、十六进制以及 Base64 等常见格式
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > MaxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		switch c := id[i]; {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case strings.IndexByte("-_.:/+=", c) >= 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// RequestIDFromContext 返回 context 中携带的请求 ID
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// ContextWithTrace 返回携带链路追踪 trace ID 以及 span ID 的 context
+func ContextWithTrace(ctx context.Context, traceID, spanID string) context.Context {
+	return context.WithValue(ctx, traceKey{}, trace{traceID: traceID, spanID: spanID})
+}
+
+// TraceFromContext 返回 context 中携带的 trace ID 以及 span ID
+func TraceFromContext(ctx context.Context) (traceID, spanID string) {
+	if ctx == nil {
+		return "", ""
+	}
+	t, _ := ctx.Value(traceKey{}).(trace)
+	return t.traceID, t.spanID
+}
+
+// ContextWithUserID 返回携带用户 ID 的 context
+func ContextWithUserID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, userIDKey{}, id)
+}
+
+// UserIDFromContext 返回 context 中携带的用户 ID
+func UserIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(userIDKey{}).(string)
+	return id
+}
+
+var (
+	extractorsMu sync.RWMutex
+	extractors   []func(ctx context.Context) []Field
+)
+
+// AddContextExtractor 添加从 context 中提取字段的函数，
+// 可用于对接 OpenTelemetry 等链路追踪库，从其 span 中提取 trace ID
+func AddContextExtractor(fn func(ctx context.Context) []Field) {
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
+	extractors = append(extractors, fn)
+}
+
+// ContextFields 返回从 context 中提取的字段，依次为请求 ID、trace ID、span ID、用户 ID、
+// AddContextExtractor 提取的字段以及 ContextWithFields 附加的字段
+func ContextFields(ctx context.Context) []Field {
+	if ctx == nil {
+		return nil
+	}
+	var fields []Field
+	if id := RequestIDFromContext(ctx); id != "" {
+		fields = append(fields, String(RequestIDKey, id))
+	}
+	if traceID, spanID := TraceFromContext(ctx); traceID != "" {
+		fields = append(fields, String(TraceIDKey, traceID))
+		if spanID != "" {
+			fields = append(fields, String(SpanIDKey, spanID))
+		}
+	}
+	if id := UserIDFromContext(ctx); id != "" {
+		fields = append(fields, String(UserIDKey, id))
+	}
+	extractorsMu.RLock()
+	for _, fn := range extractors {
+		fields = append(fields, fn(ctx)...)
+	}
+	extractorsMu.RUnlock()
+	if fs, ok := ctx.Value(fieldsKey{}).([]Field); ok {
+		fields = append(fields, fs...)
+	}
+	return fields
+}
+
+// Ctx 创建附加了 context 中字段的子 Logger
+func (l *Logger) Ctx(ctx context.Context) *Logger {
+	fields := ContextFields(ctx)
+	if len(fields) == 0 {
+		return l
+	}
+	return l.With(fields...)
+}
+
+func (l *Logger) DebugContext(ctx context.Context, msg string, fields ...Field) {
+	l.l.Debug(msg, withContextFields(ctx, fields)...)
+}
+
+func (l *Logger) InfoContext(ctx context.Context, msg string, fields ...Field) {
+	l.l.Info(msg, withContextFields(ctx, fields)...)
+}
+
+func (l *Logger) WarnContext(ctx context.Context, msg string, fields ...Field) {
+	l.l.Warn(msg, withContextFields(ctx, fields)...)
+}
+
+func (l *Logger) ErrorContext(ctx context.Context, msg string, fields ...Field) {
+	l.l.Error(msg, withContextFields(ctx, fields)...)
+}
+
+func (l *Logger) PanicContext(ctx context.Context, msg string, fields ...Field) {
+	l.l.Panic(msg, withContextFields(ctx, fields)...)
+}
+
+func (l *Logger) FatalContext(ctx context.Context, msg string, fields ...Field) {
+	l.l.Fatal(msg, withContextFields(ctx, fields)...)
+}
+
+func withContextFields(ctx context.Context, fields []Field) []Field {
+	cf := ContextFields(ctx)
+	if len(cf) == 0 {
+		return fields
+	}
+	return append(cf, fields...)
+}
+
+// WithContext 返回 context 中携带的 Logger（没有时为默认 Logger），并附加 context 中的字段
+func WithContext(ctx context.Context) *Logger { return FromContext(ctx).Ctx(ctx) }
+
+func DebugContext(ctx context.Context, msg string, fields ...Field) {
+	FromContext(ctx).l.Debug(msg, withContextFields(ctx, fields)...)
+}
+
+func InfoContext(ctx context.Context, msg string, fields ...Field) {
+	FromContext(ctx).l.Info(msg, withContextFields(ctx, fields)...)
+}
+
+func WarnContext(ctx context.Context, msg string, fields ...Field) {
+	FromContext(ctx).l.Warn(msg, withContextFields(ctx, fields)...)
+}
+
+func ErrorContext(ctx context.Context, msg string, fields ...Field) {
+	FromContext(ctx).l.Error(msg, withContextFields(ctx, fields)...)
+}
+
+func PanicContext(ctx context.Context, msg string, fields ...Field) {
+	FromContext(ctx).l.Panic(msg, withContextFields(ctx, fields)...)
+}
+
+func FatalContext(ctx context.Context, msg string, fields ...Field) {
+	FromContext(ctx).l.Fatal(msg, withContextFields(ctx, fields)...)
+}
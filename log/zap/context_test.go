@@ -0,0 +1,110 @@
+package zap
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextLogging(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf, InfoLevel, WithKeyNames(KeyNames{Time: "-"}))
+
+	ctx := ContextWithRequestID(context.Background(), "req-1")
+	ctx = ContextWithTrace(ctx, "trace-1", "span-1")
+	ctx = ContextWithUserID(ctx, "user-1")
+	ctx = ContextWithFields(ctx, String("tenant", "t1"))
+	ctx = ContextWithFields(ctx, Int("shard", 2))
+
+	l.InfoContext(ctx, "ctx msg", Bool("ok", true))
+	l.Ctx(ctx).Warn("ctx logger")
+	l.Ctx(context.Background()).Info("no fields")
+
+	assert.Equal(t, `{"level":"info","msg":"ctx msg","requestID":"req-1","traceID":"trace-1","spanID":"span-1","userID":"user-1","tenant":"t1","shard":2,"ok":true}
+{"level":"warn","msg":"ctx logger","requestID":"req-1","traceID":"trace-1","spanID":"span-1","userID":"user-1","tenant":"t1","shard":2}
+{"level":"info","msg":"no fields"}
+`, buf.String())
+
+	assert.Equal(t, "req-1", RequestIDFromContext(ctx))
+	assert.Equal(t, "user-1", UserIDFromContext(ctx))
+	traceID, spanID := TraceFromContext(ctx)
+	assert.Equal(t, "trace-1", traceID)
+	assert.Equal(t, "span-1", spanID)
+}
+
+func TestContextFieldsAreNotShared(t *testing.T) {
+	base := ContextWithFields(context.Background(), String("a", "1"), String("b", "2"))
+	ctx1 := ContextWithFields(base, String("c", "3"))
+	ctx2 := ContextWithFields(base, String("d", "4"))
+
+	assert.Equal(t, []Field{String("a", "1"), String("b", "2"), String("c", "3")}, ContextFields(ctx1))
+	assert.Equal(t, []Field{String("a", "1"), String("b", "2"), String("d", "4")}, ContextFields(ctx2))
+}
+
+func TestContextLogger(t *testing.T) {
+	old := Default()
+	defer ReplaceDefault(old)
+
+	var std, named bytes.Buffer
+	ReplaceDefault(New(&std, InfoLevel, WithKeyNames(KeyNames{Time: "-"})))
+	ctx := ContextWithRequestID(context.Background(), "req-1")
+
+	InfoContext(ctx, "default")
+	WithContext(ctx).Info("with context")
+
+	ctx = NewContext(ctx, New(&named, InfoLevel, WithKeyNames(KeyNames{Time: "-"})).Named("http"))
+	ErrorContext(ctx, "from context")
+	WithContext(ctx).Warn("with context")
+
+	assert.Equal(t, `{"level":"info","msg":"default","requestID":"req-1"}
+{"level":"info","msg":"with context","requestID":"req-1"}
+`, std.String())
+	assert.Equal(t, `{"level":"error","logger":"http","msg":"from context","requestID":"req-1"}
+{"level":"warn","logger":"http","msg":"with context","requestID":"req-1"}
+`, named.String())
+}
+
+func TestNilContext(t *testing.T) {
+	old := Default()
+	defer ReplaceDefault(old)
+
+	var buf bytes.Buffer
+	ReplaceDefault(New(&buf, InfoLevel, WithKeyNames(KeyNames{Time: "-"})))
+
+	var ctx context.Context
+	assert.Same(t, Default(), FromContext(ctx))
+	InfoContext(ctx, "nil context")
+	WithContext(ctx).Warn("with context")
+	Default().ErrorContext(ctx, "logger")
+	assert.Equal(t, `{"level":"info","msg":"nil context"}
+{"level":"warn","msg":"with context"}
+{"level":"error","msg":"logger"}
+`, buf.String())
+
+	assert.Empty(t, RequestIDFromContext(ctx))
+	assert.Empty(t, UserIDFromContext(ctx))
+	traceID, spanID := TraceFromContext(ctx)
+	assert.Empty(t, traceID)
+	assert.Empty(t, spanID)
+}
+
+func TestContextExtractor(t *testing.T) {
+	defer func(old []func(context.Context) []Field) { extractors = old }(extractors)
+	AddContextExtractor(func(ctx context.Context) []Field {
+		return []Field{String("region", "cn")}
+	})
+	assert.Equal(t, []Field{String(RequestIDKey, "req-1"), String("region", "cn")},
+		ContextFields(ContextWithRequestID(context.Background(), "req-1")))
+}
+
+func TestValidRequestID(t *testing.T) {
+	for _, id := range []string{"req-1", "0123456789abcdef0123456789abcdef", "6ba7b810-9dad-11d1-80b4-00c04fd430c8", "YWJj+/8=", "svc:1.2_3"} {
+		assert.True(t, ValidRequestID(id), id)
+	}
+	for _, id := range []string{"", "a b", "req\n{\"level\":\"error\"}", "中文", strings.Repeat("a", MaxRequestIDLength+1)} {
+		assert.False(t, ValidRequestID(id), id)
+	}
+}
@@ -0,0 +1,224 @@
+package zap
+
+import (
+	"io"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// 时间格式，WithTimeFormat 也可以使用 time.Format 的自定义格式
+const (
+	TimeFormatRFC3339     = "rfc3339"     // 默认格式，如 2023-03-19T21:57:59+08:00
+	TimeFormatRFC3339Nano = "rfc3339nano" // 如 2023-03-19T21:57:59.123456789+08:00
+	TimeFormatISO8601     = "iso8601"     // 如 2023-03-19T21:57:59.123+0800
+	TimeFormatEpoch       = "epoch"       // Unix 时间戳（秒），浮点数
+	TimeFormatEpochMillis = "epochMillis" // Unix 时间戳（毫秒），浮点数
+	TimeFormatEpochNanos  = "epochNanos"  // Unix 时间戳（纳秒），整数
+)
+
+// KeyNames 日志中各项的键名，为空时使用默认键名，为 "-" 时不输出该项
+type KeyNames struct {
+	Time       string // 默认 ts
+	Level      string // 默认 level
+	Name       string // 默认 logger
+	Caller     string // 默认 caller
+	Function   string // 默认不输出
+	Message    string // 默认 msg
+	Stacktrace string // 默认 stacktrace
+}
+
+// encoderOption 设置日志编码器等创建输出时使用的选项，嵌入 zap.Option 使其可以与 zap 选项一起传入 New、NewTee
+// 在创建 Logger 之外使用（如 Logger.WithOptions、zap.New）时应用嵌入的 zap.Option
+type encoderOption struct {
+	zap.Option
+	fn func(*encoderConfig)
+}
+
+// newEncoderOption 创建编码器选项，编码器选项只在创建 Logger（New、NewTee、LogConfig.Build）时生效，
+// 在创建 Logger 之外使用时不会修改已有的输出
+func newEncoderOption(fn func(*encoderConfig)) Option {
+	return encoderOption{
+		Option: zap.WrapCore(func(core zapcore.Core) zapcore.Core { return core }),
+		fn:     fn,
+	}
+}
+
+// newCoreOption 创建包装 Core 的选项，创建 Logger 时由 fn 设置，在创建 Logger 之外使用时通过 wrap 包装已有的 Core
+func newCoreOption(fn func(*encoderConfig), wrap func(zapcore.Core) zapcore.Core) Option {
+	return encoderOption{Option: zap.WrapCore(wrap), fn: fn}
+}
+
+type encoderConfig struct {
+	zapcore.EncoderConfig
+	console  bool
+	levelSet bool      // 是否指定了日志级别格式
+	redactor *Redactor // 敏感信息脱敏规则
+
+	sampling *SamplingConfig
+	dedupe   time.Duration
+}
+
+// WithConsoleEncoder 使用适合本地开发阅读的控制台编码器，默认使用带颜色的大写日志级别
+func WithConsoleEncoder() Option {
+	return newEncoderOption(func(c *encoderConfig) {
+		c.console = true
+	})
+}
+
+// WithJSONEncoder 使用 JSON 编码器，为默认编码器
+func WithJSONEncoder() Option {
+	return newEncoderOption(func(c *encoderConfig) {
+		c.console = false
+	})
+}
+
+// WithKeyNames 设置日志中各项的键名
+func WithKeyNames(keys KeyNames) Option {
+	return newEncoderOption(func(c *encoderConfig) {
+		for _, k := range []struct {
+			dst *string
+			src string
+		}{
+			{&c.TimeKey, keys.Time},
+			{&c.LevelKey, keys.Level},
+			{&c.NameKey, keys.Name},
+			{&c.CallerKey, keys.Caller},
+			{&c.FunctionKey, keys.Function},
+			{&c.MessageKey, keys.Message},
+			{&c.StacktraceKey, keys.Stacktrace},
+		} {
+			switch k.src {
+			case "":
+			case "-":
+				*k.dst = zapcore.OmitKey
+			default:
+				*k.dst = k.src
+			}
+		}
+	})
+}
+
+// WithTimeFormat 设置时间格式，format 为 TimeFormat* 常量或者 time.Format 的自定义格式
+func WithTimeFormat(format string) Option {
+	return newEncoderOption(func(c *encoderConfig) {
+		c.EncodeTime = timeEncoder(format)
+	})
+}
+
+// WithLevelFormat 设置日志级别格式：lower（默认）、capital、color、capitalColor
+func WithLevelFormat(format string) Option {
+	return newEncoderOption(func(c *encoderConfig) {
+		switch format {
+		case "capital":
+			c.EncodeLevel = zapcore.CapitalLevelEncoder
+		case "color":
+			c.EncodeLevel = zapcore.LowercaseColorLevelEncoder
+		case "capitalColor":
+			c.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		default:
+			c.EncodeLevel = zapcore.LowercaseLevelEncoder
+		}
+		c.levelSet = true
+	})
+}
+
+// WithDurationFormat 设置 Duration 字段格式：seconds（默认，浮点数）、ms、nanos、string（如 1.5s）
+func WithDurationFormat(format string) Option {
+	return newEncoderOption(func(c *encoderConfig) {
+		switch format {
+		case "ms":
+			c.EncodeDuration = zapcore.MillisDurationEncoder
+		case "nanos":
+			c.EncodeDuration = zapcore.NanosDurationEncoder
+		case "string":
+			c.EncodeDuration = zapcore.StringDurationEncoder
+		default:
+			c.EncodeDuration = zapcore.SecondsDurationEncoder
+		}
+	})
+}
+
+// WithCallerFormat 设置调用位置格式：short（默认，如 zap/log.go:42）、full（完整路径）
+func WithCallerFormat(format string) Option {
+	return newEncoderOption(func(c *encoderConfig) {
+		if format == "full" {
+			c.EncodeCaller = zapcore.FullCallerEncoder
+		} else {
+			c.EncodeCaller = zapcore.ShortCallerEncoder
+		}
+	})
+}
+
+// WithEncoderConfig 直接修改 zap 的编码器配置，用于以上选项无法满足的场景
+func WithEncoderConfig(fn func(*zapcore.EncoderConfig)) Option {
+	return newEncoderOption(func(c *encoderConfig) {
+		fn(&c.EncoderConfig)
+	})
+}
+
+func timeEncoder(format string) zapcore.TimeEncoder {
+	switch format {
+	case TimeFormatRFC3339:
+		return zapcore.RFC3339TimeEncoder
+	case TimeFormatRFC3339Nano:
+		return zapcore.RFC3339NanoTimeEncoder
+	case TimeFormatISO8601:
+		return zapcore.ISO8601TimeEncoder
+	case TimeFormatEpoch:
+		return zapcore.EpochTimeEncoder
+	case TimeFormatEpochMillis:
+		return zapcore.EpochMillisTimeEncoder
+	case TimeFormatEpochNanos:
+		return zapcore.EpochNanosTimeEncoder
+	}
+	return func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+		enc.AppendString(t.Format(format))
+	}
+}
+
+// splitOptions 将编码器选项与 zap 选项分开，返回创建 Core 使用的配置以及 zap 选项
+func splitOptions(opts []Option) (*encoderConfig, []Option) {
+	cfg := &encoderConfig{EncoderConfig: zap.NewProductionEncoderConfig()}
+	cfg.EncodeTime = zapcore.RFC3339TimeEncoder
+
+	zopts := make([]Option, 0, len(opts))
+	for _, opt := range opts {
+		if eo, ok := opt.(encoderOption); ok {
+			eo.fn(cfg)
+			continue
+		}
+		zopts = append(zopts, opt)
+	}
+	if cfg.console && !cfg.levelSet {
+		cfg.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	}
+	return cfg, zopts
+}
+
+// outputCore 创建写入单个输出的 Core
+func (c *encoderConfig) outputCore(out io.Writer, enabler zapcore.LevelEnabler) zapcore.Core {
+	var enc zapcore.Encoder
+	if c.console {
+		enc = zapcore.NewConsoleEncoder(c.EncoderConfig)
+	} else {
+		enc = zapcore.NewJSONEncoder(c.EncoderConfig)
+	}
+	core := newCore(enc, out, enabler)
+	if c.redactor != nil {
+		core = &redactCore{Core: core, r: c.redactor}
+	}
+	return core
+}
+
+// wrap 对所有输出合并后的 Core 进行采样以及去重
+func (c *encoderConfig) wrap(core zapcore.Core) zapcore.Core {
+	if s := c.sampling; s != nil {
+		core = zapcore.NewSamplerWithOptions(core, s.tick(), s.Initial, s.Thereafter)
+	}
+	if c.dedupe > 0 {
+		core = NewDedupeCore(core, c.dedupe)
+	}
+	return core
+}
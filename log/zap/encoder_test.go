@@ -0,0 +1,110 @@
+package zap
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+)
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time                         { return time.Time(c) }
+func (c fixedClock) NewTicker(d time.Duration) *time.Ticker { return time.NewTicker(d) }
+
+var clock = fixedClock(time.Date(2023, 3, 19, 21, 57, 59, 123456789, time.UTC))
+
+func TestEncoderOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{
+			name: "default",
+			want: `{"level":"info","ts":"2023-03-19T21:57:59Z","msg":"hello","backoff":1.5}` + "\n",
+		},
+		{
+			name: "key names",
+			opts: []Option{WithKeyNames(KeyNames{Time: "time", Level: "severity", Message: "message"})},
+			want: `{"severity":"info","time":"2023-03-19T21:57:59Z","message":"hello","backoff":1.5}` + "\n",
+		},
+		{
+			name: "omit time",
+			opts: []Option{WithKeyNames(KeyNames{Time: "-"})},
+			want: `{"level":"info","msg":"hello","backoff":1.5}` + "\n",
+		},
+		{
+			name: "epoch millis",
+			opts: []Option{WithTimeFormat(TimeFormatEpochMillis)},
+			want: `{"level":"info","ts":1679263079123.4568,"msg":"hello","backoff":1.5}` + "\n",
+		},
+		{
+			name: "rfc3339nano",
+			opts: []Option{WithTimeFormat(TimeFormatRFC3339Nano)},
+			want: `{"level":"info","ts":"2023-03-19T21:57:59.123456789Z","msg":"hello","backoff":1.5}` + "\n",
+		},
+		{
+			name: "custom layout",
+			opts: []Option{WithTimeFormat("2006-01-02 15:04:05.000")},
+			want: `{"level":"info","ts":"2023-03-19 21:57:59.123","msg":"hello","backoff":1.5}` + "\n",
+		},
+		{
+			name: "level and duration",
+			opts: []Option{WithLevelFormat("capital"), WithDurationFormat("string")},
+			want: `{"level":"INFO","ts":"2023-03-19T21:57:59Z","msg":"hello","backoff":"1.5s"}` + "\n",
+		},
+		{
+			name: "console",
+			opts: []Option{WithConsoleEncoder(), WithTimeFormat(TimeFormatRFC3339)},
+			want: "2023-03-19T21:57:59Z\t\x1b[34mINFO\x1b[0m\thello\t{\"backoff\": 1.5}\n",
+		},
+		{
+			name: "console without color",
+			opts: []Option{WithConsoleEncoder(), WithLevelFormat("capital")},
+			want: "2023-03-19T21:57:59Z\tINFO\thello\t{\"backoff\": 1.5}\n",
+		},
+		{
+			name: "encoder config",
+			opts: []Option{WithEncoderConfig(func(c *zapcore.EncoderConfig) { c.LevelKey = "lvl" })},
+			want: `{"lvl":"info","ts":"2023-03-19T21:57:59Z","msg":"hello","backoff":1.5}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, newLogger := range map[string]func(buf *bytes.Buffer) *Logger{
+				"New": func(buf *bytes.Buffer) *Logger {
+					return New(buf, InfoLevel, append(tt.opts, WithClock(clock))...)
+				},
+				"NewTee": func(buf *bytes.Buffer) *Logger {
+					tees := []TeeOption{{Out: buf, LevelEnablerFunc: func(Level) bool { return true }}}
+					return NewTee(tees, append(tt.opts, WithClock(clock))...)
+				},
+			} {
+				var buf bytes.Buffer
+				newLogger(&buf).Info("hello", Duration("backoff", 1500*time.Millisecond))
+				assert.Equal(t, tt.want, buf.String(), name)
+			}
+		})
+	}
+}
+
+func TestCallerFormat(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf, InfoLevel, AddCaller(), WithCallerFormat("full"))
+	l.Info("hello")
+	assert.Regexp(t, `"caller":"/.+/log/zap/encoder_test.go:\d+"`, buf.String())
+}
+
+func TestEncoderOptionsWithOptions(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf, InfoLevel, WithKeyNames(KeyNames{Time: "-"}))
+	// 编码器选项无法对已经创建的输出生效，不会修改子 Logger 的输出
+	assert.NotPanics(t, func() {
+		l.WithOptions(WithConsoleEncoder(), WithKeyNames(KeyNames{Message: "message"})).Info("child")
+	})
+	assert.Equal(t, `{"level":"info","msg":"child"}`+"\n", buf.String())
+	assert.NotPanics(t, func() { l.WithOptions(AddCaller(), WithRedaction(DefaultRedactRules())) })
+}
@@ -3,23 +3,40 @@ package main
 import (
 	"fmt"
 	"net/http"
+	"os"
 
 	"github.com/gin-gonic/gin"
 
 	log "github.com/jianghushinian/gokit/log/zap"
+	"github.com/jianghushinian/gokit/log/zap/ginlog"
+	"github.com/jianghushinian/gokit/log/zap/httplog"
 )
 
 func main() {
+	// custom logger
+	// logger := log.New(os.Stderr, log.InfoLevel, log.AddCaller())
+	// log.ReplaceDefault(logger)
+
+	// 隐藏日志中的 Authorization、Cookie 等敏感信息
+	log.ReplaceDefault(log.New(os.Stderr, log.InfoLevel, log.WithRedaction(log.DefaultRedactRules())))
+
 	// r := gin.Default()
 	gin.SetMode(gin.ReleaseMode)
 	r := gin.New()
-	r.Use(LoggerMiddleware(), RecoveryMiddleware())
+	opts := []httplog.Option{
+		httplog.WithSkipPaths("/healthz"),
+		httplog.WithRequestHeaders("Authorization", "Content-Type"),
+	}
+	r.Use(ginlog.Logger(opts...), ginlog.Recovery(opts...))
 
 	r.GET("/ping", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
 			"message": "pong",
 		})
 	})
+	r.GET("/healthz", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
 
 	port := ":8000"
 	log.Info(fmt.Sprintf("Listening and serving HTTP on %s", port))
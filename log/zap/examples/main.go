@@ -39,7 +39,6 @@ func main() {
 		opts := []log.Option{
 			// 附加日志调用信息
 			log.WithCaller(true),
-			log.AddCallerSkip(1),
 			// Warn 级别日志 Hook
 			log.Hooks(func(entry zapcore.Entry) error {
 				if entry.Level == log.WarnLevel {
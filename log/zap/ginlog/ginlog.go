@@ -0,0 +1,86 @@
+// Package ginlog 提供 gin 的请求日志以及 panic 恢复中间件，选项与 httplog 相同
+package ginlog
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	log "github.com/jianghushinian/gokit/log/zap"
+	"github.com/jianghushinian/gokit/log/zap/httplog"
+)
+
+// Logger 返回记录请求日志的 gin 中间件，日志中额外记录匹配的路由以及 gin.Context 中的私有错误
+// 客户端 IP 与 httplog 相同，可信代理通过 httplog.WithTrustedProxies 设置
+func Logger(opts ...httplog.Option) gin.HandlerFunc {
+	return logger(false, opts)
+}
+
+// LoggerWithGinClientIP 与 Logger 相同，但是使用 gin.Context.ClientIP 作为客户端 IP，
+// 可信代理通过 gin.Engine.SetTrustedProxies 设置，httplog.WithTrustedProxies 不再生效
+func LoggerWithGinClientIP(opts ...httplog.Option) gin.HandlerFunc {
+	return logger(true, opts)
+}
+
+func logger(ginClientIP bool, opts []httplog.Option) gin.HandlerFunc {
+	l := httplog.New(opts...)
+	return func(c *gin.Context) {
+		e := l.Start(c.Writer, c.Request)
+		c.Request = e.Request
+		if e.Skipped() {
+			c.Next()
+			return
+		}
+		c.Writer = &responseWriter{ResponseWriter: c.Writer, entry: e}
+		c.Next()
+
+		e.Route = c.FullPath()
+		if ginClientIP {
+			e.ClientIP = c.ClientIP()
+		}
+		var fields []log.Field
+		if errs := c.Errors.ByType(gin.ErrorTypePrivate); len(errs) > 0 {
+			fields = append(fields, log.String("error", errs.String()))
+		}
+		e.Finish(c.Writer.Status(), int64(c.Writer.Size()), fields...)
+	}
+}
+
+// Recovery 返回恢复 panic 的 gin 中间件，记录 Error 级别日志并响应 500 状态码
+func Recovery(opts ...httplog.Option) gin.HandlerFunc {
+	l := httplog.New(opts...)
+	return func(c *gin.Context) {
+		defer func() {
+			if err := recover(); err != nil {
+				if l.Recover(c.Request, err) {
+					// 连接已经断开，无法写入响应
+					if e, ok := err.(error); ok {
+						_ = c.Error(e)
+					}
+					c.Abort()
+					return
+				}
+				c.AbortWithStatus(http.StatusInternalServerError)
+			}
+		}()
+		c.Next()
+	}
+}
+
+// responseWriter 记录写入的响应体
+type responseWriter struct {
+	gin.ResponseWriter
+	entry *httplog.Entry
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	w.entry.CaptureResponse(p[:n])
+	return n, err
+}
+
+func (w *responseWriter) WriteString(s string) (int, error) {
+	n, err := w.ResponseWriter.WriteString(s)
+	w.entry.CaptureResponse([]byte(s[:n]))
+	return n, err
+}
@@ -0,0 +1,137 @@
+package ginlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	log "github.com/jianghushinian/gokit/log/zap"
+	"github.com/jianghushinian/gokit/log/zap/httplog"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var lines []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var m map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &m), line)
+		lines = append(lines, m)
+	}
+	return lines
+}
+
+func newRouter(buf *bytes.Buffer, opts ...httplog.Option) *gin.Engine {
+	opts = append([]httplog.Option{
+		httplog.WithLogger(log.New(buf, log.DebugLevel, log.WithKeyNames(log.KeyNames{Time: "-"}))),
+	}, opts...)
+	r := gin.New()
+	r.Use(Logger(opts...), Recovery(opts...))
+	return r
+}
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	r := newRouter(&buf, httplog.WithSkipPaths("/healthz"), httplog.WithResponseBody(64))
+	r.GET("/users/:id", func(c *gin.Context) {
+		_ = c.Error(errors.New("cache miss"))
+		c.String(http.StatusOK, "user %s, secret=%s", c.Param("id"), "s3cr3t")
+	})
+	r.GET("/healthz", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	req.Header.Set("X-Request-ID", "upstream")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "upstream", w.Header().Get("X-Request-ID"))
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.NotEmpty(t, w.Header().Get("X-Request-ID"))
+
+	lines := decodeLines(t, &buf)
+	require.Len(t, lines, 1)
+	entry := lines[0]
+	assert.IsType(t, float64(0), entry["latency"])
+	delete(entry, "latency")
+	assert.Equal(t, map[string]interface{}{
+		"level":        "info",
+		"msg":          "HTTP request",
+		"requestID":    "upstream",
+		"method":       "GET",
+		"path":         "/users/1",
+		"route":        "/users/:id",
+		"status":       float64(200),
+		"clientIP":     "192.0.2.1",
+		"userAgent":    "",
+		"requestSize":  float64(0),
+		"responseSize": float64(21),
+		"responseBody": "******",
+		"error":        "Error #01: cache miss\n",
+	}, entry)
+}
+
+func TestRecovery(t *testing.T) {
+	var buf bytes.Buffer
+	r := newRouter(&buf)
+	r.POST("/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/panic", strings.NewReader("{}")))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	lines := decodeLines(t, &buf)
+	require.Len(t, lines, 2)
+	assert.Equal(t, "HTTP panic recovered", lines[0]["msg"])
+	assert.Equal(t, "boom", lines[0]["error"])
+	assert.Equal(t, "error", lines[1]["level"])
+	assert.Equal(t, float64(500), lines[1]["status"])
+	assert.Equal(t, float64(2), lines[1]["requestSize"])
+}
+
+func TestLoggerClientIP(t *testing.T) {
+	serve := func(r *gin.Engine) {
+		r.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Forwarded-For", "203.0.113.1")
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	// 默认使用 httplog 解析的客户端 IP，不受 gin 可信代理配置的影响
+	var buf bytes.Buffer
+	serve(newRouter(&buf))
+	assert.Equal(t, "192.0.2.1", decodeLines(t, &buf)[0]["clientIP"])
+
+	buf.Reset()
+	serve(newRouter(&buf, httplog.WithTrustedProxies("192.0.2.1")))
+	assert.Equal(t, "203.0.113.1", decodeLines(t, &buf)[0]["clientIP"])
+
+	// 显式使用 gin 的 ClientIP
+	for _, proxies := range [][]string{nil, {"192.0.2.1"}} {
+		buf.Reset()
+		r := gin.New()
+		require.NoError(t, r.SetTrustedProxies(proxies))
+		r.Use(LoggerWithGinClientIP(httplog.WithLogger(log.New(&buf, log.DebugLevel))))
+		serve(r)
+		want := "192.0.2.1"
+		if proxies != nil {
+			want = "203.0.113.1"
+		}
+		assert.Equal(t, want, decodeLines(t, &buf)[0]["clientIP"])
+	}
+}
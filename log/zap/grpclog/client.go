@@ -0,0 +1,116 @@
+package grpclog
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	log "github.com/jianghushinian/gokit/log/zap"
+)
+
+// UnaryClientInterceptor 返回记录调用日志的一元客户端拦截器
+// context 中携带请求 ID 时会通过 metadata 传递给服务端
+func UnaryClientInterceptor(opts ...Option) grpc.UnaryClientInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		c := o.startClient(ctx, method, "unary", cc)
+		var p peer.Peer
+		err := invoker(c.ctx, method, req, reply, cc, append(callOpts, grpc.Peer(&p))...)
+		if p.Addr != nil {
+			c.fields = append(c.fields, log.String("peer", p.Addr.String()))
+		}
+		c.stats.request(req)
+		if err == nil {
+			c.stats.response(reply)
+		}
+		c.finish(err)
+		return err
+	}
+}
+
+// StreamClientInterceptor 返回记录调用日志的流式客户端拦截器
+// 日志在接收消息返回错误（包括 io.EOF）或者非服务端流的调用收到响应时记录，没有读取完响应的流不会记录日志
+func StreamClientInterceptor(opts ...Option) grpc.StreamClientInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		c := o.startClient(ctx, method, streamKind(desc.ClientStreams, desc.ServerStreams), cc)
+		cs, err := streamer(c.ctx, desc, cc, method, callOpts...)
+		if err != nil {
+			c.finish(err)
+			return nil, err
+		}
+		return &clientStream{ClientStream: cs, c: c, serverStreams: desc.ServerStreams}, nil
+	}
+}
+
+// startClient 通过 metadata 传递 context 中的请求 ID
+func (o *options) startClient(ctx context.Context, method, kind string, cc *grpc.ClientConn) *call {
+	if id := log.RequestIDFromContext(ctx); id != "" {
+		if md, _ := metadata.FromOutgoingContext(ctx); len(md.Get(RequestIDMetadataKey)) == 0 {
+			ctx = metadata.AppendToOutgoingContext(ctx, RequestIDMetadataKey, id)
+		}
+	}
+	if o.logger != nil {
+		ctx = log.NewContext(ctx, o.logger)
+	}
+	return &call{
+		o:      o,
+		ctx:    ctx,
+		msg:    "gRPC client call",
+		kind:   kind,
+		method: method,
+		start:  time.Now(),
+		fields: []log.Field{log.String("method", method), log.String("target", cc.Target())},
+	}
+}
+
+type clientStream struct {
+	grpc.ClientStream
+	c             *call
+	serverStreams bool
+
+	mu   sync.Mutex
+	once sync.Once
+}
+
+func (s *clientStream) SendMsg(m interface{}) error {
+	err := s.ClientStream.SendMsg(m)
+	if err == nil {
+		s.mu.Lock()
+		s.c.stats.request(m)
+		s.mu.Unlock()
+	}
+	return err
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	s.mu.Lock()
+	if err == nil {
+		s.c.stats.response(m)
+	}
+	s.mu.Unlock()
+	switch {
+	case err == io.EOF:
+		s.finish(nil)
+	case err != nil:
+		s.finish(err)
+	case !s.serverStreams:
+		// 非服务端流的调用只会收到一条响应
+		s.finish(nil)
+	}
+	return err
+}
+
+func (s *clientStream) finish(err error) {
+	s.once.Do(func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.c.finish(err)
+	})
+}
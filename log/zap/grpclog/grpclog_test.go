@@ -0,0 +1,234 @@
+package grpclog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	log "github.com/jianghushinian/gokit/log/zap"
+)
+
+// syncBuffer 可以并发写入以及读取的 bytes.Buffer
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// lines 将每行 JSON 日志解码为 map，并移除耗时等不固定的字段
+func (b *syncBuffer) lines(t *testing.T) []map[string]interface{} {
+	t.Helper()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var lines []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(b.buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var m map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &m), line)
+		delete(m, "latency")
+		delete(m, "peer")
+		delete(m, "stack")
+		lines = append(lines, m)
+	}
+	b.buf.Reset()
+	return lines
+}
+
+// healthServer 用于测试的健康检查服务，按照 service 返回不同的结果
+type healthServer struct {
+	healthpb.UnimplementedHealthServer
+}
+
+func (healthServer) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	log.FromContext(ctx).InfoContext(ctx, "check", log.String("service", req.Service))
+	switch req.Service {
+	case "panic":
+		panic("boom")
+	case "missing":
+		return nil, status.Error(codes.NotFound, "unknown service")
+	}
+	return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+}
+
+func (healthServer) Watch(req *healthpb.HealthCheckRequest, ss healthpb.Health_WatchServer) error {
+	if req.Service == "panic" {
+		panic("boom")
+	}
+	for _, s := range []healthpb.HealthCheckResponse_ServingStatus{healthpb.HealthCheckResponse_NOT_SERVING, healthpb.HealthCheckResponse_SERVING} {
+		if err := ss.Send(&healthpb.HealthCheckResponse{Status: s}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newClient 启动使用拦截器的进程内服务端，返回使用拦截器的客户端
+func newClient(t *testing.T, server, client *syncBuffer) healthpb.HealthClient {
+	t.Helper()
+	newLogger := func(w io.Writer) *log.Logger {
+		return log.New(w, log.DebugLevel, log.WithKeyNames(log.KeyNames{Time: "-"}))
+	}
+
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(UnaryServerInterceptor(WithLogger(newLogger(server)))),
+		grpc.ChainStreamInterceptor(StreamServerInterceptor(WithLogger(newLogger(server)))),
+	)
+	healthpb.RegisterHealthServer(s, healthServer{})
+	go func() { _ = s.Serve(lis) }()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(UnaryClientInterceptor(WithLogger(newLogger(client)))),
+		grpc.WithChainStreamInterceptor(StreamClientInterceptor(WithLogger(newLogger(client)))),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return healthpb.NewHealthClient(conn)
+}
+
+func TestUnaryInterceptor(t *testing.T) {
+	var server, client syncBuffer
+	c := newClient(t, &server, &client)
+
+	ctx := log.ContextWithRequestID(context.Background(), "req-1")
+	var header metadata.MD
+	_, err := c.Check(ctx, &healthpb.HealthCheckRequest{Service: "svc"}, grpc.Header(&header))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"req-1"}, header.Get(RequestIDMetadataKey))
+
+	assert.Equal(t, []map[string]interface{}{
+		{"level": "info", "msg": "check", "method": "/grpc.health.v1.Health/Check", "requestID": "req-1", "service": "svc"},
+		{
+			"level": "info", "msg": "gRPC server call", "method": "/grpc.health.v1.Health/Check", "requestID": "req-1",
+			"kind": "unary", "code": "OK", "requestSize": float64(5), "responseSize": float64(2),
+		},
+	}, server.lines(t))
+	assert.Equal(t, []map[string]interface{}{
+		{
+			"level": "info", "msg": "gRPC client call", "requestID": "req-1", "method": "/grpc.health.v1.Health/Check", "target": "bufnet",
+			"kind": "unary", "code": "OK", "requestSize": float64(5), "responseSize": float64(2),
+		},
+	}, client.lines(t))
+
+	_, err = c.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	lines := server.lines(t)
+	require.Len(t, lines, 2)
+	assert.Len(t, lines[1]["requestID"], 32)
+	assert.Equal(t, "NotFound", lines[1]["code"])
+	assert.Equal(t, "unknown service", lines[1]["error"])
+	assert.Equal(t, "info", lines[1]["level"])
+	assert.Equal(t, "NotFound", client.lines(t)[0]["code"])
+}
+
+func TestUnaryInterceptorPanic(t *testing.T) {
+	var server, client syncBuffer
+	c := newClient(t, &server, &client)
+
+	_, err := c.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "panic"})
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	lines := server.lines(t)
+	require.Len(t, lines, 3)
+	assert.Equal(t, "gRPC panic recovered", lines[1]["msg"])
+	assert.Equal(t, "boom", lines[1]["error"])
+	assert.Equal(t, "/grpc.health.v1.Health/Check", lines[1]["method"])
+	assert.Equal(t, "error", lines[2]["level"])
+	assert.Equal(t, "Internal", lines[2]["code"])
+	assert.Equal(t, "error", client.lines(t)[0]["level"])
+}
+
+func TestStreamInterceptor(t *testing.T) {
+	var server, client syncBuffer
+	c := newClient(t, &server, &client)
+
+	stream, err := c.Watch(context.Background(), &healthpb.HealthCheckRequest{Service: "svc"})
+	require.NoError(t, err)
+	for {
+		if _, err = stream.Recv(); err != nil {
+			break
+		}
+	}
+	assert.Equal(t, io.EOF, err)
+
+	want := map[string]interface{}{
+		"level": "info", "method": "/grpc.health.v1.Health/Watch", "kind": "server_stream", "code": "OK",
+		"requestSize": float64(5), "responseSize": float64(4), "requestMessages": float64(1), "responseMessages": float64(2),
+	}
+	lines := client.lines(t)
+	require.Len(t, lines, 1)
+	assert.Equal(t, "bufnet", lines[0]["target"])
+	delete(lines[0], "target")
+	want["msg"] = "gRPC client call"
+	assert.Equal(t, want, lines[0])
+
+	lines = server.lines(t)
+	require.Len(t, lines, 1)
+	assert.Len(t, lines[0]["requestID"], 32)
+	delete(lines[0], "requestID")
+	want["msg"] = "gRPC server call"
+	assert.Equal(t, want, lines[0])
+
+	stream, err = c.Watch(context.Background(), &healthpb.HealthCheckRequest{Service: "panic"})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Internal, status.Code(err))
+	lines = server.lines(t)
+	require.Len(t, lines, 2)
+	assert.Equal(t, "gRPC panic recovered", lines[0]["msg"])
+	assert.Equal(t, "Internal", lines[1]["code"])
+}
+
+func TestSkipMethods(t *testing.T) {
+	var buf syncBuffer
+	l := log.New(&buf, log.DebugLevel)
+	interceptor := UnaryServerInterceptor(WithLogger(l), WithSkipMethods("/grpc.health.v1.Health/Check"))
+	info := &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}
+
+	_, err := interceptor(context.Background(), &healthpb.HealthCheckRequest{}, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		panic("boom")
+	})
+	assert.Equal(t, codes.Internal, status.Code(err))
+	lines := buf.lines(t)
+	require.Len(t, lines, 1)
+	assert.Equal(t, "gRPC panic recovered", lines[0]["msg"])
+}
+
+func TestInvalidRequestID(t *testing.T) {
+	o := newOptions(nil)
+	for _, id := range []string{"", "a b", "req\n{\"level\":\"error\"}", strings.Repeat("a", log.MaxRequestIDLength+1)} {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(RequestIDMetadataKey, id))
+		c := o.startServer(ctx, "/svc/Method", "unary")
+		got := log.RequestIDFromContext(c.ctx)
+		assert.NotEqual(t, id, got)
+		assert.Regexp(t, `^[0-9a-f]{32}$`, got)
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(RequestIDMetadataKey, "req-1"))
+	assert.Equal(t, "req-1", log.RequestIDFromContext(o.startServer(ctx, "/svc/Method", "unary").ctx))
+}
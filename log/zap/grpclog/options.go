@@ -0,0 +1,69 @@
+package grpclog
+
+import (
+	"google.golang.org/grpc/codes"
+
+	log "github.com/jianghushinian/gokit/log/zap"
+)
+
+// RequestIDMetadataKey 传递请求 ID 的 metadata 键
+const RequestIDMetadataKey = "x-request-id"
+
+// Option 拦截器选项
+type Option func(*options)
+
+type options struct {
+	logger      *log.Logger
+	skipMethods map[string]struct{}
+	codeLevel   func(code codes.Code) log.Level
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		skipMethods: make(map[string]struct{}),
+		codeLevel:   DefaultCodeLevel,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithLogger 设置记录日志的 Logger，默认使用 context 中携带的 Logger（没有时为默认 Logger）
+func WithLogger(l *log.Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
+
+// WithSkipMethods 不记录指定方法的日志，如 /grpc.health.v1.Health/Check，
+// 服务端仍会恢复 panic 以及在 context 中放入 Logger
+func WithSkipMethods(methods ...string) Option {
+	return func(o *options) {
+		for _, m := range methods {
+			o.skipMethods[m] = struct{}{}
+		}
+	}
+}
+
+// WithCodeLevel 设置状态码对应的日志级别，默认为 DefaultCodeLevel
+func WithCodeLevel(fn func(code codes.Code) log.Level) Option {
+	return func(o *options) {
+		o.codeLevel = fn
+	}
+}
+
+// DefaultCodeLevel 默认的状态码对应的日志级别：
+// 服务端错误（Unknown、Unimplemented、Internal、DataLoss）为 Error，
+// 可能需要关注的错误（DeadlineExceeded、PermissionDenied、ResourceExhausted、FailedPrecondition、Aborted、OutOfRange、Unavailable）为 Warn，
+// 其他为 Info
+func DefaultCodeLevel(code codes.Code) log.Level {
+	switch code {
+	case codes.Unknown, codes.Unimplemented, codes.Internal, codes.DataLoss:
+		return log.ErrorLevel
+	case codes.DeadlineExceeded, codes.PermissionDenied, codes.ResourceExhausted, codes.FailedPrecondition,
+		codes.Aborted, codes.OutOfRange, codes.Unavailable:
+		return log.WarnLevel
+	}
+	return log.InfoLevel
+}
@@ -0,0 +1,206 @@
+// Package grpclog 提供记录 gRPC 调用日志的服务端以及客户端拦截器
+package grpclog
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	log "github.com/jianghushinian/gokit/log/zap"
+)
+
+// UnaryServerInterceptor 返回记录调用日志的一元服务端拦截器，panic 会被恢复为 codes.Internal 错误
+// 调用方法、对端地址以及请求 ID 会附加在 context 中的 Logger 上，处理程序中可以通过 log.FromContext 获取
+func UnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		c := o.startServer(ctx, info.FullMethod, "unary")
+		_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDMetadataKey, log.RequestIDFromContext(c.ctx)))
+		defer func() {
+			if r := recover(); r != nil {
+				err = c.recover(r)
+			}
+			c.stats.request(req)
+			if err == nil {
+				c.stats.response(resp)
+			}
+			c.finish(err)
+		}()
+		return handler(c.ctx, req)
+	}
+}
+
+// StreamServerInterceptor 返回记录调用日志的流式服务端拦截器，panic 会被恢复为 codes.Internal 错误
+// 请求、响应大小为所有消息大小之和
+func StreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
+	o := newOptions(opts)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		c := o.startServer(ss.Context(), info.FullMethod, streamKind(info.IsClientStream, info.IsServerStream))
+		_ = ss.SetHeader(metadata.Pairs(RequestIDMetadataKey, log.RequestIDFromContext(c.ctx)))
+		defer func() {
+			if r := recover(); r != nil {
+				err = c.recover(r)
+			}
+			c.finish(err)
+		}()
+		return handler(srv, &serverStream{ServerStream: ss, ctx: c.ctx, stats: &c.stats})
+	}
+}
+
+// call 单次调用的日志
+type call struct {
+	o      *options
+	ctx    context.Context
+	msg    string
+	kind   string
+	method string
+	start  time.Time
+	stats  stats
+	fields []log.Field
+}
+
+// startServer 从 metadata 中获取或生成请求 ID，并在 context 中放入附加了调用信息的 Logger
+// metadata 中的请求 ID 由调用方控制，无效时（参考 log.ValidRequestID）重新生成
+func (o *options) startServer(ctx context.Context, method, kind string) *call {
+	id := metadataValue(metadata.ValueFromIncomingContext(ctx, RequestIDMetadataKey))
+	if !log.ValidRequestID(id) {
+		id = newRequestID()
+	}
+	ctx = log.ContextWithRequestID(ctx, id)
+
+	fields := []log.Field{log.String("method", method)}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		fields = append(fields, log.String("peer", p.Addr.String()))
+	}
+	l := o.logger
+	if l == nil {
+		l = log.FromContext(ctx)
+	}
+	ctx = log.NewContext(ctx, l.With(fields...))
+	return &call{o: o, ctx: ctx, msg: "gRPC server call", kind: kind, method: method, start: time.Now()}
+}
+
+// recover 记录 panic 以及调用栈，返回 codes.Internal 错误
+func (c *call) recover(r interface{}) error {
+	log.FromContext(c.ctx).ErrorContext(c.ctx, "gRPC panic recovered", log.Any("error", r), log.Stack("stack"))
+	return status.Error(codes.Internal, "internal error")
+}
+
+func (c *call) finish(err error) {
+	if _, ok := c.o.skipMethods[c.method]; ok {
+		return
+	}
+	code := status.Code(err)
+	fields := append(c.fields,
+		log.String("kind", c.kind),
+		log.String("code", code.String()),
+		log.Duration("latency", time.Since(c.start)),
+	)
+	fields = append(fields, c.stats.fields(c.kind != "unary")...)
+	if err != nil {
+		fields = append(fields, log.String("error", status.Convert(err).Message()))
+	}
+
+	l := log.FromContext(c.ctx)
+	switch c.o.codeLevel(code) {
+	case log.DebugLevel:
+		l.DebugContext(c.ctx, c.msg, fields...)
+	case log.InfoLevel:
+		l.InfoContext(c.ctx, c.msg, fields...)
+	case log.WarnLevel:
+		l.WarnContext(c.ctx, c.msg, fields...)
+	default:
+		l.ErrorContext(c.ctx, c.msg, fields...)
+	}
+}
+
+// stats 统计请求、响应的消息数量以及大小
+type stats struct {
+	requests, responses       int
+	requestSize, responseSize int
+}
+
+func (s *stats) request(m interface{}) {
+	s.requests++
+	s.requestSize += size(m)
+}
+
+func (s *stats) response(m interface{}) {
+	s.responses++
+	s.responseSize += size(m)
+}
+
+func (s *stats) fields(stream bool) []log.Field {
+	fields := []log.Field{log.Int("requestSize", s.requestSize), log.Int("responseSize", s.responseSize)}
+	if stream {
+		fields = append(fields, log.Int("requestMessages", s.requests), log.Int("responseMessages", s.responses))
+	}
+	return fields
+}
+
+// size 返回 protobuf 消息编码后的大小，不是 protobuf 消息时为 0
+func size(m interface{}) int {
+	if pm, ok := m.(proto.Message); ok {
+		return proto.Size(pm)
+	}
+	return 0
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx   context.Context
+	stats *stats
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *serverStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.stats.response(m)
+	}
+	return err
+}
+
+func (s *serverStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.stats.request(m)
+	}
+	return err
+}
+
+func streamKind(client, server bool) string {
+	switch {
+	case client && server:
+		return "bidi_stream"
+	case client:
+		return "client_stream"
+	case server:
+		return "server_stream"
+	}
+	return "unary"
+}
+
+func metadataValue(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
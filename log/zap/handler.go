@@ -0,0 +1,208 @@
+package zap
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// levelState 日志级别的当前状态
+type levelState struct {
+	Level   *Level                `json:"level,omitempty"`   // 默认日志级别，NewTee 创建的 Logger 没有默认日志级别
+	Loggers map[string]Level      `json:"loggers"`           // 按名称为子 Logger 单独设置的日志级别
+	Outputs map[string]LevelRange `json:"outputs"`           // 具名输出的日志级别范围
+	Reverts map[string]time.Time  `json:"reverts,omitempty"` // 等待自动恢复的修改及其恢复时间
+}
+
+// levelRequest 修改日志级别的请求，Logger、Output 都为空时修改默认日志级别
+type levelRequest struct {
+	Logger   string `json:"logger"`   // 子 Logger 名称或者通配符模式
+	Output   string `json:"output"`   // 输出名称
+	Level    *Level `json:"level"`    // 日志级别，修改输出时等同于 min 为 level，max 为 fatal
+	Min      *Level `json:"min"`      // 输出的最低级别
+	Max      *Level `json:"max"`      // 输出的最高级别
+	Reset    bool   `json:"reset"`    // 移除为子 Logger 单独设置的日志级别
+	Duration string `json:"duration"` // 自动恢复修改前日志级别的时间，如 10m，为空时不自动恢复
+}
+
+// LevelHandler 返回运行时查看以及修改日志级别的 http.Handler
+//
+//	GET 返回当前的日志级别：
+//	{"level":"info","loggers":{"db":"debug"},"outputs":{"file":{"min":"info","max":"fatal"}}}
+//
+//	PUT 修改日志级别，返回修改后的日志级别：
+//	{"level":"debug"}                                   修改默认日志级别
+//	{"logger":"db.*","level":"debug","duration":"10m"}  修改子 Logger 的日志级别，10 分钟后自动恢复
+//	{"logger":"db","reset":true}                        恢复子 Logger 使用默认日志级别
+//	{"output":"file","min":"debug"}                     修改输出的日志级别范围
+func (l *Logger) LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serveLevel(l, w, r)
+	})
+}
+
+// LevelHandler 返回查看以及修改默认 Logger 日志级别的 http.Handler，ReplaceDefault 之后同样生效
+func LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serveLevel(std, w, r)
+	})
+}
+
+func serveLevel(l *Logger, w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var req levelRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeLevelError(w, http.StatusBadRequest, fmt.Errorf("decode request: %w", err))
+			return
+		}
+		if err := l.applyLevelRequest(req); err != nil {
+			writeLevelError(w, http.StatusBadRequest, err)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		writeLevelError(w, http.StatusMethodNotAllowed, errors.New(http.StatusText(http.StatusMethodNotAllowed)))
+		return
+	}
+
+	state := levelState{
+		Loggers: l.NamedLevels(),
+		Outputs: l.OutputLevels(),
+		Reverts: l.levels.pendingReverts(),
+	}
+	if l.al != nil {
+		level := l.al.Level()
+		state.Level = &level
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(state)
+}
+
+func writeLevelError(w http.ResponseWriter, code int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(struct {
+		Error string `json:"error"`
+	}{err.Error()})
+}
+
+func (l *Logger) applyLevelRequest(req levelRequest) error {
+	var d time.Duration
+	if req.Duration != "" {
+		var err error
+		if d, err = time.ParseDuration(req.Duration); err != nil || d <= 0 {
+			return fmt.Errorf("invalid duration %q", req.Duration)
+		}
+	}
+
+	switch {
+	case req.Logger != "" && req.Output != "":
+		return errors.New("logger and output can not be set at the same time")
+	case req.Output != "":
+		ar, ok := l.outputs[req.Output]
+		if !ok {
+			return fmt.Errorf("unknown log output %q, outputs: %v", req.Output, l.OutputNames())
+		}
+		prev := ar.Load()
+		lr := prev
+		if req.Level != nil {
+			lr = LevelsFrom(*req.Level)
+		}
+		if req.Min != nil {
+			lr.Min = *req.Min
+		}
+		if req.Max != nil {
+			lr.Max = *req.Max
+		}
+		if err := l.SetOutputLevels(req.Output, lr); err != nil {
+			return err
+		}
+		l.levels.scheduleRevert("output:"+req.Output, d, func() { ar.Store(prev) })
+	case req.Logger != "":
+		if err := validatePattern(req.Logger); err != nil {
+			return err
+		}
+		prev, ok := l.levels.levels()[req.Logger]
+		switch {
+		case req.Reset:
+			l.ResetNamedLevel(req.Logger)
+		case req.Level != nil:
+			l.SetNamedLevel(req.Logger, *req.Level)
+		default:
+			return errors.New("level or reset is required")
+		}
+		l.levels.scheduleRevert("logger:"+req.Logger, d, func() {
+			if ok {
+				l.SetNamedLevel(req.Logger, prev)
+			} else {
+				l.ResetNamedLevel(req.Logger)
+			}
+		})
+	default:
+		if l.al == nil {
+			return errors.New("logger created by NewTee has no default level, set the level of an output instead")
+		}
+		if req.Level == nil {
+			return errors.New("level is required")
+		}
+		prev := l.al.Level()
+		l.SetLevel(*req.Level)
+		l.levels.scheduleRevert("level", d, func() { l.SetLevel(prev) })
+	}
+	return nil
+}
+
+// revert 等待自动恢复的修改
+type revert struct {
+	timer   *time.Timer
+	at      time.Time
+	restore func()
+}
+
+// scheduleRevert 在 d 之后调用 restore 恢复修改，d 为 0 时表示修改不会自动恢复
+// 同一目标已有等待自动恢复的修改时，恢复为最早一次修改之前的状态
+func (r *levelRegistry) scheduleRevert(key string, d time.Duration, restore func()) {
+	r.revertMu.Lock()
+	defer r.revertMu.Unlock()
+
+	if old, ok := r.reverts[key]; ok {
+		old.timer.Stop()
+		delete(r.reverts, key)
+		restore = old.restore
+	}
+	if d <= 0 {
+		return
+	}
+	if r.reverts == nil {
+		r.reverts = make(map[string]*revert)
+	}
+	rv := &revert{at: time.Now().Add(d), restore: restore}
+	rv.timer = time.AfterFunc(d, func() {
+		r.revertMu.Lock()
+		if r.reverts[key] != rv {
+			r.revertMu.Unlock()
+			return
+		}
+		delete(r.reverts, key)
+		r.revertMu.Unlock()
+		restore()
+	})
+	r.reverts[key] = rv
+}
+
+func (r *levelRegistry) pendingReverts() map[string]time.Time {
+	r.revertMu.Lock()
+	defer r.revertMu.Unlock()
+	if len(r.reverts) == 0 {
+		return nil
+	}
+	reverts := make(map[string]time.Time, len(r.reverts))
+	for k, rv := range r.reverts {
+		reverts[k] = rv.at
+	}
+	return reverts
+}
@@ -0,0 +1,126 @@
+package zap
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveLevelRequest(t *testing.T, h http.Handler, method, body string) (int, levelState, string) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(method, "/log/level", strings.NewReader(body)))
+	var state levelState
+	if w.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &state))
+	}
+	return w.Code, state, w.Body.String()
+}
+
+func TestLevelHandler(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf, InfoLevel, WithKeyNames(KeyNames{Time: "-"}))
+	h := l.LevelHandler()
+
+	code, state, _ := serveLevelRequest(t, h, http.MethodGet, "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, InfoLevel, *state.Level)
+	assert.Empty(t, state.Loggers)
+
+	code, state, _ = serveLevelRequest(t, h, http.MethodPut, `{"level":"warn"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, WarnLevel, *state.Level)
+	assert.Equal(t, WarnLevel, l.Level())
+
+	// 只修改 db 及其下级子 Logger 的日志级别
+	code, state, _ = serveLevelRequest(t, h, http.MethodPut, `{"logger":"db","level":"debug"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, map[string]Level{"db": DebugLevel}, state.Loggers)
+
+	l.Named("db").Named("query").Debug("db debug")
+	l.Named("http").Info("http info")
+	l.Info("root info")
+	l.Named("http").Warn("http warn")
+	assert.Equal(t, `{"level":"debug","logger":"db.query","msg":"db debug"}
+{"level":"warn","logger":"http","msg":"http warn"}
+`, buf.String())
+
+	code, state, _ = serveLevelRequest(t, h, http.MethodPut, `{"logger":"db","reset":true}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Empty(t, state.Loggers)
+
+	code, _, body := serveLevelRequest(t, h, http.MethodPut, `{"level":"verbose"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Contains(t, body, `unrecognized level: \"verbose\"`)
+
+	code, _, body = serveLevelRequest(t, h, http.MethodPut, `{"output":"file","level":"debug"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Contains(t, body, `unknown log output`)
+
+	code, _, _ = serveLevelRequest(t, h, http.MethodPost, `{"level":"debug"}`)
+	assert.Equal(t, http.StatusMethodNotAllowed, code)
+}
+
+func TestLevelHandlerTeeOutputs(t *testing.T) {
+	info := LevelsFrom(InfoLevel)
+	l := NewTee([]TeeOption{{Out: &bytes.Buffer{}, Name: "file", Levels: &info}})
+	h := l.LevelHandler()
+
+	code, state, _ := serveLevelRequest(t, h, http.MethodGet, "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Nil(t, state.Level)
+	assert.Equal(t, map[string]LevelRange{"file": info}, state.Outputs)
+
+	code, state, _ = serveLevelRequest(t, h, http.MethodPut, `{"output":"file","min":"debug","max":"warn"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, map[string]LevelRange{"file": {Min: DebugLevel, Max: WarnLevel}}, state.Outputs)
+
+	code, _, body := serveLevelRequest(t, h, http.MethodPut, `{"level":"debug"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Contains(t, body, "has no default level")
+}
+
+func TestLevelHandlerAutoRevert(t *testing.T) {
+	l := New(&bytes.Buffer{}, InfoLevel)
+	h := l.LevelHandler()
+
+	code, state, _ := serveLevelRequest(t, h, http.MethodPut, `{"level":"debug","duration":"50ms"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, DebugLevel, l.Level())
+	assert.Contains(t, state.Reverts, "level")
+
+	// 自动恢复之前再次修改，恢复为最早一次修改之前的日志级别
+	code, _, _ = serveLevelRequest(t, h, http.MethodPut, `{"level":"warn","duration":"50ms"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, WarnLevel, l.Level())
+
+	code, _, _ = serveLevelRequest(t, h, http.MethodPut, `{"logger":"db","level":"debug","duration":"50ms"}`)
+	assert.Equal(t, http.StatusOK, code)
+
+	assert.Eventually(t, func() bool {
+		return l.Level() == InfoLevel && len(l.NamedLevels()) == 0
+	}, time.Second, 10*time.Millisecond)
+	_, state, _ = serveLevelRequest(t, h, http.MethodGet, "")
+	assert.Empty(t, state.Reverts)
+
+	code, _, body := serveLevelRequest(t, h, http.MethodPut, `{"level":"debug","duration":"forever"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Contains(t, body, `invalid duration \"forever\"`)
+}
+
+func TestDefaultLevelHandler(t *testing.T) {
+	old := Default()
+	defer ReplaceDefault(old)
+	ReplaceDefault(New(&bytes.Buffer{}, InfoLevel))
+
+	code, _, _ := serveLevelRequest(t, LevelHandler(), http.MethodPut, `{"level":"error"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, ErrorLevel, GetLevel())
+}
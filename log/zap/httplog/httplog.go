@@ -0,0 +1,354 @@
+// Package httplog 提供 net/http 的请求日志以及 panic 恢复中间件
+package httplog
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/jianghushinian/gokit/log/zap"
+)
+
+// Logger 请求日志记录器，Handler、RecoveryHandler 为 net/http 中间件，
+// Start 以及 Entry.Finish 可以用于在其他 Web 框架中实现请求日志中间件，参考 ginlog
+type Logger struct {
+	o options
+}
+
+// New 创建请求日志记录器
+func New(opts ...Option) *Logger {
+	return &Logger{o: newOptions(opts)}
+}
+
+// Middleware 返回记录请求日志的 net/http 中间件
+func Middleware(opts ...Option) func(http.Handler) http.Handler {
+	return New(opts...).Handler
+}
+
+// Handler 记录请求日志，请求 ID 会放入请求的 context 中并通过响应头返回
+func (l *Logger) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		e := l.Start(w, r)
+		rw := &responseWriter{ResponseWriter: w, entry: e}
+		next.ServeHTTP(rw, e.Request)
+		if e.Skipped() {
+			return
+		}
+		e.Finish(rw.Status(), rw.size)
+	})
+}
+
+// Entry 单个请求的日志
+type Entry struct {
+	// Request 附加了请求 ID、Logger 的请求，需要传递给之后的处理程序
+	Request   *http.Request
+	RequestID string
+	// Route 匹配的路由，如 /users/:id，为空时不记录
+	Route string
+	// ClientIP 客户端 IP，为空时使用请求的 RemoteAddr，请求来自可信代理（参考 WithTrustedProxies）时使用代理传递的 IP
+	ClientIP string
+
+	l        *Logger
+	w        http.ResponseWriter
+	start    time.Time
+	skipped  bool
+	body     *bodyReader
+	respBody *bytes.Buffer
+	// respTruncated 响应体超过 WithResponseBody 设置的大小
+	respTruncated bool
+}
+
+// Start 开始记录请求日志：读取或生成请求 ID 并设置到响应头，以及按照选项记录请求体
+// 请求头中的请求 ID 无效时（参考 log.ValidRequestID）会重新生成
+func (l *Logger) Start(w http.ResponseWriter, r *http.Request) *Entry {
+	e := &Entry{l: l, w: w, start: time.Now(), skipped: l.skip(r)}
+
+	id := r.Header.Get(l.o.requestIDHeader)
+	if !log.ValidRequestID(id) {
+		// 请求头中的请求 ID 由客户端控制，无效时重新生成，避免日志注入以及超长的请求 ID
+		id = l.o.newRequestID()
+	}
+	e.RequestID = id
+	w.Header().Set(l.o.requestIDHeader, id)
+	ctx := log.ContextWithRequestID(r.Context(), id)
+	if l.o.logger != nil {
+		ctx = log.NewContext(ctx, l.o.logger)
+	}
+	r = r.WithContext(ctx)
+
+	if !e.skipped && r.Body != nil && r.Body != http.NoBody {
+		e.body = &bodyReader{ReadCloser: r.Body}
+		if l.o.requestBody > 0 {
+			e.body.capture(l.o.requestBody)
+		}
+		r.Body = e.body
+	}
+	if !e.skipped && l.o.responseBody > 0 {
+		e.respBody = new(bytes.Buffer)
+	}
+	e.Request = r
+	return e
+}
+
+func (l *Logger) skip(r *http.Request) bool {
+	if _, ok := l.o.skipPaths[r.URL.Path]; ok {
+		return true
+	}
+	return l.o.skipper != nil && l.o.skipper(r)
+}
+
+// Skipped 返回是否跳过该请求的日志
+func (e *Entry) Skipped() bool {
+	return e.skipped
+}
+
+// CaptureResponse 记录写入的响应体，超过 WithResponseBody 设置的大小时响应体被截断，日志中整体记录为 Mask
+func (e *Entry) CaptureResponse(p []byte) {
+	if e.respBody == nil {
+		return
+	}
+	if n := e.l.o.responseBody - e.respBody.Len(); n > 0 {
+		if len(p) > n {
+			p = p[:n]
+			e.respTruncated = true
+		}
+		e.respBody.Write(p)
+	} else if len(p) > 0 {
+		e.respTruncated = true
+	}
+}
+
+// Finish 记录请求日志，status、size 为响应状态码以及响应体大小，fields 会附加在日志最后
+// 状态码为 5xx 时记录 Error 级别日志，4xx 时记录 Warn 级别日志，其他为 Info 级别
+func (e *Entry) Finish(status int, size int64, fields ...log.Field) {
+	if e.skipped {
+		return
+	}
+	r, o := e.Request, e.l.o
+	ip := e.ClientIP
+	if ip == "" {
+		ip = o.clientIP(r)
+	}
+	fs := make([]log.Field, 0, 16+len(fields))
+	fs = append(fs,
+		log.String("method", r.Method),
+		log.String("path", r.URL.Path),
+	)
+	if e.Route != "" {
+		fs = append(fs, log.String("route", e.Route))
+	}
+	if r.URL.RawQuery != "" {
+		fs = append(fs, log.String("query", redactQuery(o.redactor, r.URL.RawQuery)))
+	}
+	fs = append(fs,
+		log.Int("status", status),
+		log.Duration("latency", time.Since(e.start)),
+		log.String("clientIP", ip),
+		log.String("userAgent", r.UserAgent()),
+		log.Int64("requestSize", e.requestSize()),
+		log.Int64("responseSize", size),
+	)
+	if h := captureHeaders(o.redactor, r.Header, o.requestHeaders); h != nil {
+		fs = append(fs, log.Any("requestHeaders", h))
+	}
+	if h := captureHeaders(o.redactor, e.w.Header(), o.responseHeaders); h != nil {
+		fs = append(fs, log.Any("responseHeaders", h))
+	}
+	if e.body != nil && e.body.buf != nil && e.body.buf.Len() > 0 {
+		fs = append(fs, log.String("requestBody", redactBody(o.redactor, r.Header.Get("Content-Type"), e.body.buf.String(), e.body.truncated)))
+	}
+	if e.respBody != nil && e.respBody.Len() > 0 {
+		fs = append(fs, log.String("responseBody", redactBody(o.redactor, e.w.Header().Get("Content-Type"), e.respBody.String(), e.respTruncated)))
+	}
+	fs = append(fs, fields...)
+
+	const msg = "HTTP request"
+	l := e.logger()
+	switch {
+	case status >= http.StatusInternalServerError:
+		l.ErrorContext(r.Context(), msg, fs...)
+	case status >= http.StatusBadRequest:
+		l.WarnContext(r.Context(), msg, fs...)
+	default:
+		l.InfoContext(r.Context(), msg, fs...)
+	}
+}
+
+func (e *Entry) logger() *log.Logger {
+	return log.FromContext(e.Request.Context())
+}
+
+// requestSize 请求体大小，请求头中没有 Content-Length 时为已读取的字节数
+func (e *Entry) requestSize() int64 {
+	if e.Request.ContentLength >= 0 {
+		return e.Request.ContentLength
+	}
+	if e.body != nil {
+		return e.body.n
+	}
+	return 0
+}
+
+// bodyReader 统计读取的请求体大小，并记录请求体的前 max 个字节
+type bodyReader struct {
+	io.ReadCloser
+	n   int64
+	buf *bytes.Buffer
+	// truncated 请求体超过 max 个字节，buf 中只有部分请求体
+	truncated bool
+}
+
+// capture 预先读取请求体的前 max 个字节，使得处理程序没有读取请求体时同样可以记录
+// 多读取一个字节用于判断请求体是否被截断
+func (b *bodyReader) capture(max int) {
+	buf := new(bytes.Buffer)
+	_, err := io.CopyN(buf, b.ReadCloser, int64(max)+1)
+	b.ReadCloser = readCloser{
+		Reader: io.MultiReader(bytes.NewReader(buf.Bytes()), errReader{b.ReadCloser, err}),
+		Closer: b.ReadCloser,
+	}
+	if buf.Len() > max {
+		b.truncated = true
+		buf = bytes.NewBuffer(buf.Bytes()[:max:max])
+	}
+	b.buf = buf
+}
+
+func (b *bodyReader) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n += int64(n)
+	return n, err
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// errReader 预先读取请求体出错（io.EOF 除外）时返回该错误
+type errReader struct {
+	r   io.Reader
+	err error
+}
+
+func (r errReader) Read(p []byte) (int, error) {
+	if r.err != nil && r.err != io.EOF {
+		return 0, r.err
+	}
+	return r.r.Read(p)
+}
+
+// clientIP 返回客户端 IP，请求来自可信代理时从右向左跳过 X-Forwarded-For 中的可信代理，取第一个不可信的 IP，
+// 没有 X-Forwarded-For 时使用 X-Real-IP，请求头中的 IP 格式错误时使用 RemoteAddr
+func (o *options) clientIP(r *http.Request) string {
+	remote := strings.TrimSpace(r.RemoteAddr)
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		remote = host
+	}
+	if addr, err := netip.ParseAddr(remote); err != nil || !o.trusted(addr) {
+		return remote
+	}
+	if xff := r.Header.Values("X-Forwarded-For"); len(xff) > 0 {
+		ips := strings.Split(strings.Join(xff, ","), ",")
+		for i := len(ips) - 1; i >= 0; i-- {
+			addr, err := netip.ParseAddr(strings.TrimSpace(ips[i]))
+			if err != nil {
+				return remote
+			}
+			if i == 0 || !o.trusted(addr) {
+				return addr.String()
+			}
+		}
+	}
+	if ip := strings.TrimSpace(r.Header.Get("X-Real-IP")); ip != "" {
+		if addr, err := netip.ParseAddr(ip); err == nil {
+			return addr.String()
+		}
+	}
+	return remote
+}
+
+func (o *options) trusted(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, p := range o.trustedProxies {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// captureHeaders 按照 names 记录请求头或响应头，敏感请求头的值会被隐藏
+func captureHeaders(r *log.Redactor, h http.Header, names []string) map[string]string {
+	if len(names) == 0 || len(h) == 0 {
+		return nil
+	}
+	for _, name := range names {
+		if name == "*" {
+			names = make([]string, 0, len(h))
+			for k := range h {
+				names = append(names, k)
+			}
+			sort.Strings(names)
+			break
+		}
+	}
+	captured := make(map[string]string, len(names))
+	for _, name := range names {
+		values := h.Values(name)
+		if len(values) == 0 {
+			continue
+		}
+		key := http.CanonicalHeaderKey(name)
+		if r.Sensitive(key) {
+			captured[key] = r.Mask()
+			continue
+		}
+		captured[key] = r.RedactString(strings.Join(values, ", "))
+	}
+	if len(captured) == 0 {
+		return nil
+	}
+	return captured
+}
+
+// redactQuery 隐藏查询参数中敏感参数的值以及其他参数值中的敏感内容
+func redactQuery(r *log.Redactor, query string) string {
+	params := strings.Split(query, "&")
+	for i, p := range params {
+		k, _, _ := strings.Cut(p, "=")
+		if key, err := url.QueryUnescape(k); err == nil && r.Sensitive(key) {
+			params[i] = k + "=" + r.Mask()
+		}
+	}
+	query = strings.Join(params, "&")
+	if unescaped, err := url.QueryUnescape(query); err == nil && r.RedactString(unescaped) != unescaped {
+		// 参数值中包含需要隐藏的内容（如电子邮件地址）时，按照解码后的内容处理
+		return r.RedactString(unescaped)
+	}
+	return query
+}
+
+// redactBody 隐藏请求体、响应体中的敏感信息，只有完整的 JSON 或表单数据才能按照键名识别敏感字段，
+// 被截断或无法解析的内容整体替换为 Mask，避免其中的敏感字段因无法识别键名而被记录
+func redactBody(r *log.Redactor, contentType, body string, truncated bool) string {
+	if truncated {
+		return r.Mask()
+	}
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		if _, err := url.ParseQuery(body); err != nil {
+			return r.Mask()
+		}
+		return redactQuery(r, body)
+	}
+	if s, ok := r.RedactJSON(body); ok {
+		return s
+	}
+	return r.Mask()
+}
@@ -0,0 +1,221 @@
+package httplog
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	log "github.com/jianghushinian/gokit/log/zap"
+)
+
+func newTestLogger(buf *bytes.Buffer) *log.Logger {
+	return log.New(buf, log.DebugLevel, log.WithKeyNames(log.KeyNames{Time: "-"}))
+}
+
+// decodeLines 将每行 JSON 日志解码为 map
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var lines []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var m map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &m), line)
+		lines = append(lines, m)
+	}
+	return lines
+}
+
+func TestMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	var requestID string
+	h := Middleware(
+		WithLogger(newTestLogger(&buf)),
+		WithRequestIDGenerator(func() string { return "generated" }),
+		WithRequestHeaders("Authorization", "Content-Type", "X-Missing"),
+		WithResponseHeaders("*"),
+		WithRequestBody(64),
+		WithResponseBody(16),
+	)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID = log.RequestIDFromContext(r.Context())
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.Equal(t, `{"user":"tom","password":"123456"}`, string(body))
+
+		log.FromContext(r.Context()).InfoContext(r.Context(), "handle")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, `{"id":1,"email":"tom@example.com"}`)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/users?token=abc&page=1", strings.NewReader(`{"user":"tom","password":"123456"}`))
+	req.Header.Set("Authorization", "Bearer abc")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "test")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	assert.Equal(t, "generated", requestID)
+	assert.Equal(t, "generated", w.Header().Get(DefaultRequestIDHeader))
+
+	lines := decodeLines(t, &buf)
+	require.Len(t, lines, 2)
+	assert.Equal(t, map[string]interface{}{"level": "info", "msg": "handle", "requestID": "generated"}, lines[0])
+
+	entry := lines[1]
+	assert.IsType(t, float64(0), entry["latency"])
+	delete(entry, "latency")
+	assert.Equal(t, map[string]interface{}{
+		"level":        "info",
+		"msg":          "HTTP request",
+		"requestID":    "generated",
+		"method":       "POST",
+		"path":         "/users",
+		"query":        "token=******&page=1",
+		"status":       float64(201),
+		"clientIP":     "192.0.2.1",
+		"userAgent":    "test",
+		"requestSize":  float64(34),
+		"responseSize": float64(34),
+		"requestHeaders": map[string]interface{}{
+			"Authorization": "******",
+			"Content-Type":  "application/json",
+		},
+		"responseHeaders": map[string]interface{}{
+			"Content-Type": "application/json",
+			"X-Request-Id": "generated",
+		},
+		"requestBody": `{"password":"******","user":"tom"}`,
+		// 响应体被截断，无法按照键名识别敏感字段
+		"responseBody": "******",
+	}, entry)
+}
+
+func TestMiddlewareRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	h := Middleware(WithLogger(newTestLogger(&buf)), WithRequestIDHeader("X-Trace"), WithTrustedProxies("192.0.2.1", "10.0.0.0/8"))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "not found", http.StatusNotFound)
+		}))
+
+	req := httptest.NewRequest(http.MethodGet, "/missing", nil)
+	req.Header.Set("X-Trace", "upstream")
+	req.Header.Set("X-Forwarded-For", "203.0.113.1, 10.0.0.1")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	assert.Equal(t, "upstream", w.Header().Get("X-Trace"))
+	lines := decodeLines(t, &buf)
+	require.Len(t, lines, 1)
+	assert.Equal(t, "warn", lines[0]["level"])
+	assert.Equal(t, "upstream", lines[0]["requestID"])
+	assert.Equal(t, "203.0.113.1", lines[0]["clientIP"])
+	assert.Equal(t, float64(404), lines[0]["status"])
+	assert.NotContains(t, lines[0], "requestHeaders")
+	assert.NotContains(t, lines[0], "requestBody")
+}
+
+func TestInvalidRequestID(t *testing.T) {
+	l := New(WithRequestIDGenerator(func() string { return "generated" }))
+	for _, id := range []string{"", "a b", "req\n{\"level\":\"error\"}", strings.Repeat("a", log.MaxRequestIDLength+1)} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(DefaultRequestIDHeader, id)
+		w := httptest.NewRecorder()
+		e := l.Start(w, req)
+		assert.Equal(t, "generated", e.RequestID)
+		assert.Equal(t, "generated", w.Header().Get(DefaultRequestIDHeader))
+		assert.Equal(t, "generated", log.RequestIDFromContext(e.Request.Context()))
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name    string
+		proxies []string
+		remote  string
+		headers map[string]string
+		ip      string
+	}{
+		{name: "untrusted", remote: "192.0.2.1:1234", headers: map[string]string{"X-Forwarded-For": "203.0.113.1", "X-Real-IP": "203.0.113.2"}, ip: "192.0.2.1"},
+		{name: "not proxy", proxies: []string{"10.0.0.0/8"}, remote: "192.0.2.1:1234", headers: map[string]string{"X-Forwarded-For": "203.0.113.1"}, ip: "192.0.2.1"},
+		{name: "forwarded", proxies: []string{"10.0.0.0/8"}, remote: "10.0.0.2:1234", headers: map[string]string{"X-Forwarded-For": "203.0.113.1"}, ip: "203.0.113.1"},
+		// 客户端伪造的 X-Forwarded-For 在最左侧，取可信代理之前的第一个 IP
+		{name: "spoofed", proxies: []string{"10.0.0.0/8"}, remote: "10.0.0.2:1234", headers: map[string]string{"X-Forwarded-For": "1.1.1.1, 203.0.113.1, 10.0.0.1"}, ip: "203.0.113.1"},
+		{name: "all trusted", proxies: []string{"10.0.0.0/8"}, remote: "10.0.0.2:1234", headers: map[string]string{"X-Forwarded-For": "10.0.0.3, 10.0.0.1"}, ip: "10.0.0.3"},
+		{name: "invalid", proxies: []string{"10.0.0.2"}, remote: "10.0.0.2:1234", headers: map[string]string{"X-Forwarded-For": "unknown"}, ip: "10.0.0.2"},
+		{name: "real ip", proxies: []string{"10.0.0.2"}, remote: "10.0.0.2:1234", headers: map[string]string{"X-Real-IP": "203.0.113.2"}, ip: "203.0.113.2"},
+		{name: "ipv6", proxies: []string{"::1"}, remote: "[::1]:1234", headers: map[string]string{"X-Forwarded-For": "2001:db8::1"}, ip: "2001:db8::1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := newOptions([]Option{WithTrustedProxies(tt.proxies...)})
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remote
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			assert.Equal(t, tt.ip, o.clientIP(req))
+		})
+	}
+	assert.Panics(t, func() { WithTrustedProxies("10.0.0.0/33") })
+}
+
+func TestMiddlewareSkip(t *testing.T) {
+	var buf bytes.Buffer
+	h := Middleware(
+		WithLogger(newTestLogger(&buf)),
+		WithSkipPaths("/healthz"),
+		WithSkipper(func(r *http.Request) bool { return r.Method == http.MethodOptions }),
+	)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NotEmpty(t, log.RequestIDFromContext(r.Context()))
+	}))
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/healthz", nil),
+		httptest.NewRequest(http.MethodOptions, "/users", nil),
+	} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		assert.Len(t, w.Header().Get(DefaultRequestIDHeader), 32)
+	}
+	assert.Empty(t, buf.String())
+}
+
+func TestRedactBody(t *testing.T) {
+	r := log.NewRedactor(log.DefaultRedactRules())
+	assert.Equal(t, "user=tom&password=******", redactBody(r, "application/x-www-form-urlencoded", "user=tom&password=123", false))
+	assert.Equal(t, "email=******&page=1", redactQuery(r, "email=tom%40example.com&page=1"))
+	assert.Equal(t, `{"card":"******"}`, redactBody(r, "application/json", `{"card":"4111111111111111"}`, false))
+	// 无法解析或被截断的内容中的敏感字段无法识别，整体隐藏
+	assert.Equal(t, "******", redactBody(r, "text/plain", "password: 123456", false))
+	assert.Equal(t, "******", redactBody(r, "application/x-www-form-urlencoded", "password=%zz", false))
+	assert.Equal(t, "******", redactBody(r, "application/json", `{"user":"tom"}`, true))
+}
+
+func TestMiddlewareTruncatedBody(t *testing.T) {
+	var buf bytes.Buffer
+	h := Middleware(WithLogger(newTestLogger(&buf)), WithRequestBody(40))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			assert.Equal(t, `{"user":"tom","password":"hunter2","remember":true}`, string(body))
+		}))
+
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"user":"tom","password":"hunter2","remember":true}`))
+	req.Header.Set("Content-Type", "application/json")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.NotContains(t, buf.String(), "hunter2")
+	lines := decodeLines(t, &buf)
+	require.Len(t, lines, 1)
+	assert.Equal(t, "******", lines[0]["requestBody"])
+	assert.Equal(t, float64(51), lines[0]["requestSize"])
+}
@@ -0,0 +1,156 @@
+package httplog
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	log "github.com/jianghushinian/gokit/log/zap"
+)
+
+// DefaultRequestIDHeader 默认传递请求 ID 的请求头以及响应头
+const DefaultRequestIDHeader = "X-Request-ID"
+
+// Option 请求日志中间件选项
+type Option func(*options)
+
+type options struct {
+	logger          *log.Logger
+	requestIDHeader string
+	newRequestID    func() string
+	skipPaths       map[string]struct{}
+	skipper         func(r *http.Request) bool
+	requestHeaders  []string
+	responseHeaders []string
+	requestBody     int
+	responseBody    int
+	redactor        *log.Redactor
+	trustedProxies  []netip.Prefix
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		requestIDHeader: DefaultRequestIDHeader,
+		newRequestID:    newRequestID,
+		skipPaths:       make(map[string]struct{}),
+		redactor:        log.NewRedactor(log.DefaultRedactRules()),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithLogger 设置记录日志的 Logger，同时会通过 log.NewContext 放入请求的 context 中，
+// 默认使用请求 context 中携带的 Logger（没有时为默认 Logger）
+func WithLogger(l *log.Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
+
+// WithRequestIDHeader 设置传递请求 ID 的请求头以及响应头，默认为 X-Request-ID
+func WithRequestIDHeader(name string) Option {
+	return func(o *options) {
+		o.requestIDHeader = name
+	}
+}
+
+// WithRequestIDGenerator 设置请求头中没有请求 ID 或者请求 ID 无效（参考 log.ValidRequestID）时生成请求 ID 的函数，
+// 默认生成 32 位随机十六进制字符串
+func WithRequestIDGenerator(fn func() string) Option {
+	return func(o *options) {
+		o.newRequestID = fn
+	}
+}
+
+// WithSkipPaths 不记录指定路径的请求日志，如健康检查接口，请求 ID 仍会正常生成以及传递
+func WithSkipPaths(paths ...string) Option {
+	return func(o *options) {
+		for _, p := range paths {
+			o.skipPaths[p] = struct{}{}
+		}
+	}
+}
+
+// WithSkipper 设置判断是否跳过请求日志的函数，与 WithSkipPaths 同时生效
+func WithSkipper(fn func(r *http.Request) bool) Option {
+	return func(o *options) {
+		o.skipper = fn
+	}
+}
+
+// WithRequestHeaders 记录指定的请求头，"*" 表示记录所有请求头
+func WithRequestHeaders(names ...string) Option {
+	return func(o *options) {
+		o.requestHeaders = append(o.requestHeaders, names...)
+	}
+}
+
+// WithResponseHeaders 记录指定的响应头，"*" 表示记录所有响应头
+func WithResponseHeaders(names ...string) Option {
+	return func(o *options) {
+		o.responseHeaders = append(o.responseHeaders, names...)
+	}
+}
+
+// WithRequestBody 记录不超过 max 个字节的请求体，敏感字段按照键名隐藏，
+// 请求体超过 max 个字节或不是 JSON、表单数据时无法识别敏感字段，整体记录为脱敏规则的 Mask
+func WithRequestBody(max int) Option {
+	return func(o *options) {
+		o.requestBody = max
+	}
+}
+
+// WithResponseBody 记录不超过 max 个字节的响应体，处理方式与 WithRequestBody 相同
+func WithResponseBody(max int) Option {
+	return func(o *options) {
+		o.responseBody = max
+	}
+}
+
+// WithRedactRules 设置记录请求头、查询参数以及请求体、响应体时使用的脱敏规则，默认为 log.DefaultRedactRules
+func WithRedactRules(rules log.RedactRules) Option {
+	return func(o *options) {
+		o.redactor = log.NewRedactor(rules)
+	}
+}
+
+// WithTrustedProxies 设置可信的反向代理 IP 或 CIDR（如 10.0.0.0/8），只有请求来自可信代理时才会
+// 通过 X-Forwarded-For、X-Real-IP 请求头获取客户端 IP，默认不信任任何代理，客户端 IP 为请求的 RemoteAddr
+// proxies 格式错误时 panic
+func WithTrustedProxies(proxies ...string) Option {
+	prefixes := make([]netip.Prefix, 0, len(proxies))
+	for _, p := range proxies {
+		prefix, err := parsePrefix(p)
+		if err != nil {
+			panic(fmt.Sprintf("httplog: invalid trusted proxy %q: %v", p, err))
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	return func(o *options) {
+		o.trustedProxies = append(o.trustedProxies, prefixes...)
+	}
+}
+
+func parsePrefix(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		prefix, err := netip.ParsePrefix(s)
+		return prefix.Masked(), err
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
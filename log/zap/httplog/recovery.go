@@ -0,0 +1,71 @@
+package httplog
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	log "github.com/jianghushinian/gokit/log/zap"
+)
+
+// Recovery 返回恢复 panic 的 net/http 中间件，记录 Error 级别日志并响应 500 状态码
+func Recovery(opts ...Option) func(http.Handler) http.Handler {
+	return New(opts...).RecoveryHandler
+}
+
+// RecoveryHandler 恢复 panic，记录 Error 级别日志并响应 500 状态码
+// 与 net/http 保持一致，http.ErrAbortHandler 会被重新 panic
+func (l *Logger) RecoveryHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if err := recover(); err != nil {
+				if err == http.ErrAbortHandler {
+					panic(err)
+				}
+				if !l.Recover(r, err) {
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Recover 记录 recover 得到的 err 以及调用栈，连接已经断开时返回 true，此时不应再写入响应
+// 请求头会按照脱敏规则隐藏敏感信息
+func (l *Logger) Recover(r *http.Request, err interface{}) (brokenPipe bool) {
+	brokenPipe = isBrokenPipe(err)
+	fields := []log.Field{
+		log.Any("error", err),
+		log.String("method", r.Method),
+		log.String("path", r.URL.Path),
+		log.Bool("brokenPipe", brokenPipe),
+	}
+	if h := captureHeaders(l.o.redactor, r.Header, []string{"*"}); h != nil {
+		fields = append(fields, log.Any("headers", h))
+	}
+	fields = append(fields, log.Stack("stack"))
+
+	lg := l.o.logger
+	if lg == nil {
+		lg = log.FromContext(r.Context())
+	}
+	lg.ErrorContext(r.Context(), "HTTP panic recovered", fields...)
+	return brokenPipe
+}
+
+// isBrokenPipe 检查连接是否已经断开，此时不需要调用栈，也无法写入响应
+func isBrokenPipe(err interface{}) bool {
+	ne, ok := err.(*net.OpError)
+	if !ok {
+		return false
+	}
+	var se *os.SyscallError
+	if errors.As(ne, &se) {
+		msg := strings.ToLower(se.Error())
+		return strings.Contains(msg, "broken pipe") || strings.Contains(msg, "connection reset by peer")
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package httplog
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecovery(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithLogger(newTestLogger(&buf)))
+	h := l.Handler(l.RecoveryHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})))
+
+	req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+	req.Header.Set("Cookie", "sid=1")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	lines := decodeLines(t, &buf)
+	require.Len(t, lines, 2)
+	assert.Equal(t, "HTTP panic recovered", lines[0]["msg"])
+	assert.Equal(t, "boom", lines[0]["error"])
+	assert.Equal(t, map[string]interface{}{"Cookie": "******"}, lines[0]["headers"])
+	assert.Contains(t, lines[0]["stack"], "TestRecovery")
+	assert.Equal(t, lines[0]["requestID"], lines[1]["requestID"])
+	assert.Equal(t, "error", lines[1]["level"])
+	assert.Equal(t, float64(500), lines[1]["status"])
+}
+
+func TestRecoveryAbortHandler(t *testing.T) {
+	h := Recovery()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
@@ -0,0 +1,149 @@
+package zap
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// LevelRange 日志级别范围，包含 Min 与 Max
+type LevelRange struct {
+	Min Level `yaml:"min" json:"min" toml:"min"`
+	Max Level `yaml:"max" json:"max" toml:"max"`
+}
+
+// AllLevels 包含所有日志级别的范围
+var AllLevels = LevelRange{Min: DebugLevel, Max: FatalLevel}
+
+// LevelsFrom 返回不低于 level 的日志级别范围
+func LevelsFrom(level Level) LevelRange {
+	return LevelRange{Min: level, Max: FatalLevel}
+}
+
+func (r LevelRange) Enabled(level Level) bool {
+	return level >= r.Min && level <= r.Max
+}
+
+func (r LevelRange) String() string {
+	return r.Min.String() + "-" + r.Max.String()
+}
+
+func (r LevelRange) validate() error {
+	if r.Min > r.Max {
+		return fmt.Errorf("min level %s is greater than max level %s", r.Min, r.Max)
+	}
+	return nil
+}
+
+// atomicLevelRange 可以在运行时修改的日志级别范围
+type atomicLevelRange struct {
+	p atomic.Pointer[LevelRange]
+}
+
+func newAtomicLevelRange(r LevelRange) *atomicLevelRange {
+	ar := &atomicLevelRange{}
+	ar.p.Store(&r)
+	return ar
+}
+
+func (r *atomicLevelRange) Enabled(level Level) bool {
+	return r.p.Load().Enabled(level)
+}
+
+func (r *atomicLevelRange) Load() LevelRange {
+	return *r.p.Load()
+}
+
+func (r *atomicLevelRange) Store(lr LevelRange) {
+	r.p.Store(&lr)
+}
+
+// outputLevels 具名输出的日志级别范围，创建 Logger 后不再修改，子 Logger 共用
+type outputLevels map[string]*atomicLevelRange
+
+// add 添加具名输出，名称已存在时返回已有的日志级别范围，名称为空时不可修改
+func (o outputLevels) add(name string, r *LevelRange) *atomicLevelRange {
+	if ar, ok := o[name]; ok && name != "" {
+		return ar
+	}
+	lr := AllLevels
+	if r != nil {
+		lr = *r
+	}
+	ar := newAtomicLevelRange(lr)
+	if name != "" {
+		o[name] = ar
+	}
+	return ar
+}
+
+// SetOutputLevels 修改指定名称的输出的日志级别范围，仅对 NewTee 或 LogConfig.Build 创建的具名输出有效
+func (l *Logger) SetOutputLevels(name string, r LevelRange) error {
+	ar, ok := l.outputs[name]
+	if !ok {
+		return fmt.Errorf("unknown log output %q, outputs: %v", name, l.OutputNames())
+	}
+	if err := r.validate(); err != nil {
+		return fmt.Errorf("log output %q: %w", name, err)
+	}
+	ar.Store(r)
+	return nil
+}
+
+// OutputLevels 返回所有具名输出当前的日志级别范围
+func (l *Logger) OutputLevels() map[string]LevelRange {
+	levels := make(map[string]LevelRange, len(l.outputs))
+	for name, ar := range l.outputs {
+		levels[name] = ar.Load()
+	}
+	return levels
+}
+
+// OutputNames 返回所有具名输出的名称
+func (l *Logger) OutputNames() []string {
+	return sortedNames(l.outputs)
+}
+
+func SetOutputLevels(name string, r LevelRange) error { return std.SetOutputLevels(name, r) }
+func OutputLevels() map[string]LevelRange             { return std.OutputLevels() }
+
+// andEnabler 所有 LevelEnabler 都启用时才启用
+type andEnabler []zapcore.LevelEnabler
+
+func (e andEnabler) Enabled(level Level) bool {
+	for _, le := range e {
+		if !le.Enabled(level) {
+			return false
+		}
+	}
+	return true
+}
+
+// stepLevel 将默认日志级别调整 delta 级，范围为 Debug 到 Error，并记录一条日志，已经到达边界时不做任何处理
+func (l *Logger) stepLevel(delta int, reason fmt.Stringer) {
+	if l.al == nil {
+		return
+	}
+	from := l.al.Level()
+	to := from + Level(delta)
+	if to < DebugLevel {
+		to = DebugLevel
+	}
+	if to > ErrorLevel {
+		to = ErrorLevel
+	}
+	if to == from {
+		return
+	}
+	l.SetLevel(to)
+
+	// 使用不低于 Info 的级别记录，确保调整后的日志级别下仍然可以输出
+	level := to
+	if level < InfoLevel {
+		level = InfoLevel
+	}
+	if ce := l.l.Check(level, "log level changed"); ce != nil {
+		ce.Write(String("from", from.String()), String("to", to.String()), Stringer("reason", reason))
+	}
+}
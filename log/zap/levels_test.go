@@ -0,0 +1,91 @@
+package zap
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTeeOutputLevels(t *testing.T) {
+	var stderr, file bytes.Buffer
+	warn, info := LevelsFrom(WarnLevel), LevelsFrom(InfoLevel)
+	l := NewTee([]TeeOption{
+		{Out: &stderr, Name: "stderr", Levels: &warn},
+		{Out: &file, Name: "file", Levels: &info},
+	}, WithKeyNames(KeyNames{Time: "-"}))
+
+	assert.Equal(t, map[string]LevelRange{"stderr": warn, "file": info}, l.OutputLevels())
+
+	child := l.Named("child")
+	child.Debug("debug before")
+	child.Warn("warn before")
+
+	// 排查问题时将文件输出的日志级别调整为 Debug，stderr 保持 Warn
+	require.NoError(t, l.SetOutputLevels("file", AllLevels))
+	child.Debug("debug after")
+	child.Info("info after")
+
+	assert.Equal(t, `{"level":"warn","logger":"child","msg":"warn before"}
+`, stderr.String())
+	assert.Equal(t, `{"level":"warn","logger":"child","msg":"warn before"}
+{"level":"debug","logger":"child","msg":"debug after"}
+{"level":"info","logger":"child","msg":"info after"}
+`, file.String())
+	assert.Equal(t, AllLevels, child.OutputLevels()["file"])
+
+	assert.EqualError(t, l.SetOutputLevels("unknown", AllLevels), `unknown log output "unknown", outputs: [file stderr]`)
+	assert.EqualError(t, l.SetOutputLevels("file", LevelRange{Min: ErrorLevel, Max: InfoLevel}),
+		`log output "file": min level error is greater than max level info`)
+}
+
+func TestTeeLevelEnablerFunc(t *testing.T) {
+	var buf bytes.Buffer
+	all := AllLevels
+	l := NewTee([]TeeOption{{
+		Out:              &buf,
+		Name:             "out",
+		Levels:           &all,
+		LevelEnablerFunc: func(level Level) bool { return level != WarnLevel },
+	}}, WithKeyNames(KeyNames{Time: "-"}))
+
+	l.Warn("warn")
+	require.NoError(t, l.SetOutputLevels("out", LevelRange{Min: ErrorLevel, Max: FatalLevel}))
+	l.Info("info")
+	l.Error("error")
+
+	assert.Equal(t, `{"level":"error","msg":"error"}
+`, buf.String())
+}
+
+func TestLogConfigOutputLevels(t *testing.T) {
+	debug, warn := DebugLevel, WarnLevel
+	cfg := &LogConfig{Outputs: []OutputConfig{
+		{Name: "console", Path: "stdout", MinLevel: &warn},
+		{Path: "stderr"},
+	}}
+	l, err := cfg.Build()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]LevelRange{"console": LevelsFrom(WarnLevel), "stderr": AllLevels}, l.OutputLevels())
+
+	cfg.Outputs[0].MinLevel = &debug
+	l.ApplyConfig(cfg)
+	assert.Equal(t, AllLevels, l.OutputLevels()["console"])
+}
+
+func TestStepLevel(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf, WarnLevel, WithKeyNames(KeyNames{Time: "-"}))
+	l.stepLevel(1, DebugLevel)
+	l.stepLevel(1, DebugLevel)
+	// 已经是 Error 级别时不再调整，也不会记录日志
+	assert.Equal(t, ErrorLevel, l.Level())
+	assert.Equal(t, `{"level":"error","msg":"log level changed","from":"warn","to":"error","reason":"debug"}`+"\n", buf.String())
+
+	buf.Reset()
+	l.SetLevel(DebugLevel)
+	l.stepLevel(-1, DebugLevel)
+	assert.Equal(t, DebugLevel, l.Level())
+	assert.Empty(t, buf.String())
+}
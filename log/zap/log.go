@@ -1,8 +1,10 @@
 package zap
 
 import (
+	"errors"
 	"io"
 	"os"
+	"sync"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -21,8 +23,43 @@ const (
 
 type Logger struct {
 	l *zap.Logger
+	s *zap.SugaredLogger
 	// https://pkg.go.dev/go.uber.org/zap#example-AtomicLevel
 	al *zap.AtomicLevel
+	// 按名称为子 Logger 单独设置的日志级别
+	levels *levelRegistry
+	// 具名输出的日志级别范围
+	outputs outputLevels
+	// LogConfig.Build 打开的输出，由 Close 关闭
+	closer *closer
+}
+
+// newLogger 创建 Logger，core 只需要处理输出的日志级别范围，默认日志级别以及按名称设置的日志级别由 reg 处理
+// 创建的 zap.Logger 会跳过 Logger 方法这一层调用，使日志调用位置为用户代码所在行，
+// 包级别函数直接调用默认 Logger 内部的 zap.Logger，调用层数与 Logger 方法一致
+// opts 中包含 AddCallerSkip 时以其为准，兼容之前版本中自行传入 AddCallerSkip(1) 的用法
+func newLogger(core zapcore.Core, reg *levelRegistry, opts []Option) *Logger {
+	if !hasCallerSkip(opts) {
+		opts = append([]Option{zap.AddCallerSkip(1)}, opts...)
+	}
+	l := zap.New(&levelCore{Core: core, reg: reg}, opts...)
+	return &Logger{l: l, s: l.Sugar(), al: reg.al, levels: reg}
+}
+
+func hasCallerSkip(opts []Option) bool {
+	for _, opt := range opts {
+		if _, ok := opt.(callerSkipOption); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// clone 创建使用 l 的子 Logger，子 Logger 与父 Logger 共用日志级别
+func (l *Logger) clone(zl *zap.Logger) *Logger {
+	c := *l
+	c.l, c.s = zl, zl.Sugar()
+	return &c
 }
 
 func New(out io.Writer, level Level, opts ...Option) *Logger {
@@ -31,26 +68,36 @@ func New(out io.Writer, level Level, opts ...Option) *Logger {
 	}
 
 	al := zap.NewAtomicLevelAt(level)
-	cfg := zap.NewProductionEncoderConfig()
-	cfg.EncodeTime = zapcore.RFC3339TimeEncoder
-
-	core := zapcore.NewCore(
-		zapcore.NewJSONEncoder(cfg),
-		zapcore.AddSync(out),
-		al,
-	)
-	return &Logger{l: zap.New(core, opts...), al: &al}
+	cfg, opts := splitOptions(opts)
+	return newLogger(cfg.wrap(cfg.outputCore(out, AllLevels)), newLevelRegistry(&al), opts)
 }
 
 // SetLevel 动态更改日志级别
 // 对于使用 NewTee 创建的 Logger 无效，因为 NewTee 本意是根据不同日志级别
-// 创建的多个 zap.Core，不应该通过 SetLevel 将多个 zap.Core 日志级别统一
+// 创建的多个 zap.Core，不应该通过 SetLevel 将多个 zap.Core 日志级别统一，
+// 需要通过 SetOutputLevels 分别修改每个具名输出的日志级别范围
 func (l *Logger) SetLevel(level Level) {
 	if l.al != nil {
 		l.al.SetLevel(level)
 	}
 }
 
+// With 创建附加了字段的子 Logger，子 Logger 与父 Logger 共用日志级别
+func (l *Logger) With(fields ...Field) *Logger {
+	return l.clone(l.l.With(fields...))
+}
+
+// Named 创建指定名称的子 Logger，名称以 "." 拼接在父 Logger 的名称之后
+func (l *Logger) Named(name string) *Logger {
+	return l.clone(l.l.Named(name))
+}
+
+// WithOptions 创建应用了 zap 选项的子 Logger，脱敏、采样、合并重复日志选项对子 Logger 的所有输出生效，
+// 编码器选项只能在创建 Logger 时使用，在此传入不会生效
+func (l *Logger) WithOptions(opts ...Option) *Logger {
+	return l.clone(l.l.WithOptions(opts...))
+}
+
 type Field = zap.Field
 
 func (l *Logger) Debug(msg string, fields ...Field) {
@@ -81,6 +128,41 @@ func (l *Logger) Sync() error {
 	return l.l.Sync()
 }
 
+// Close 同步并关闭 LogConfig.Build 打开的输出（包括写入缓冲区中剩余的日志并停止异步写入的后台 goroutine），
+// 之后不应再使用 Logger 及其子 Logger 记录日志，多次调用只会关闭一次
+// 对于 New、NewTee 创建的 Logger 只会调用 Sync，传入的 io.Writer 需要自行关闭
+func (l *Logger) Close() error {
+	if l.closer == nil {
+		return l.Sync()
+	}
+	return l.closer.close(l.Sync)
+}
+
+// closer 关闭 Logger 打开的输出，子 Logger 与父 Logger 共用
+type closer struct {
+	once    sync.Once
+	closers []io.Closer
+	err     error
+}
+
+// close 调用 sync 后关闭所有输出，只会执行一次
+func (c *closer) close(sync func() error) error {
+	c.once.Do(func() {
+		c.err = errors.Join(sync(), closeAll(c.closers))
+	})
+	return c.err
+}
+
+func closeAll(closers []io.Closer) error {
+	var errs []error
+	for _, c := range closers {
+		if err := c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
 var std = New(os.Stderr, InfoLevel)
 
 func Default() *Logger         { return std }
@@ -88,11 +170,15 @@ func ReplaceDefault(l *Logger) { std = l }
 
 func SetLevel(level Level) { std.SetLevel(level) }
 
-func Debug(msg string, fields ...Field) { std.Debug(msg, fields...) }
-func Info(msg string, fields ...Field)  { std.Info(msg, fields...) }
-func Warn(msg string, fields ...Field)  { std.Warn(msg, fields...) }
-func Error(msg string, fields ...Field) { std.Error(msg, fields...) }
-func Panic(msg string, fields ...Field) { std.Panic(msg, fields...) }
-func Fatal(msg string, fields ...Field) { std.Fatal(msg, fields...) }
+func With(fields ...Field) *Logger       { return std.With(fields...) }
+func Named(name string) *Logger          { return std.Named(name) }
+func WithOptions(opts ...Option) *Logger { return std.WithOptions(opts...) }
+
+func Debug(msg string, fields ...Field) { std.l.Debug(msg, fields...) }
+func Info(msg string, fields ...Field)  { std.l.Info(msg, fields...) }
+func Warn(msg string, fields ...Field)  { std.l.Warn(msg, fields...) }
+func Error(msg string, fields ...Field) { std.l.Error(msg, fields...) }
+func Panic(msg string, fields ...Field) { std.l.Panic(msg, fields...) }
+func Fatal(msg string, fields ...Field) { std.l.Fatal(msg, fields...) }
 
 func Sync() error { return std.Sync() }
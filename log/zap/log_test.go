@@ -0,0 +1,47 @@
+package zap
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChildLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf, InfoLevel, WithKeyNames(KeyNames{Time: "-"}))
+
+	child := l.Named("db").With(String("table", "users")).Named("query")
+	child.Info("select", Int("rows", 3))
+	child.WithOptions(Fields(Bool("cached", true))).Infow("hit", "key", "user:1")
+	child.Infof("%d rows affected", 2)
+
+	// 子 Logger 与父 Logger 共用日志级别
+	l.SetLevel(WarnLevel)
+	child.Info("ignored")
+	child.Warnw("slow query", "elapsed", "2s")
+
+	assert.Equal(t, `{"level":"info","logger":"db.query","msg":"select","table":"users","rows":3}
+{"level":"info","logger":"db.query","msg":"hit","table":"users","cached":true,"key":"user:1"}
+{"level":"info","logger":"db.query","msg":"2 rows affected","table":"users"}
+{"level":"warn","logger":"db.query","msg":"slow query","table":"users","elapsed":"2s"}
+`, buf.String())
+}
+
+func TestDefaultHelpers(t *testing.T) {
+	old := Default()
+	defer ReplaceDefault(old)
+
+	var buf bytes.Buffer
+	ReplaceDefault(New(&buf, DebugLevel, WithKeyNames(KeyNames{Time: "-"})))
+	Named("app").With(String("env", "test")).Debug("start")
+	Infof("listening on %s", ":8000")
+	Errorw("failed", "attempt", 3)
+	With(Int("id", 1)).Warn("warn")
+
+	assert.Equal(t, `{"level":"debug","logger":"app","msg":"start","env":"test"}
+{"level":"info","msg":"listening on :8000"}
+{"level":"error","msg":"failed","attempt":3}
+{"level":"warn","msg":"warn","id":1}
+`, buf.String())
+}
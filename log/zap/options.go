@@ -12,9 +12,20 @@ var (
 	Development   = zap.Development
 	AddCaller     = zap.AddCaller
 	WithCaller    = zap.WithCaller
-	AddCallerSkip = zap.AddCallerSkip
 	AddStacktrace = zap.AddStacktrace
 	IncreaseLevel = zap.IncreaseLevel
 	WithFatalHook = zap.WithFatalHook
 	WithClock     = zap.WithClock
 )
+
+// callerSkipOption AddCallerSkip 创建的选项，用于创建 Logger 时判断是否自行设置了跳过的调用层数
+type callerSkipOption struct {
+	zap.Option
+}
+
+// AddCallerSkip 增加记录调用位置时跳过的调用层数
+// 创建 Logger 时传入该选项会替代默认跳过的 Logger 方法这一层调用，与之前版本需要传入 AddCallerSkip(1) 的用法保持一致，
+// 通过 WithOptions 传入时与 zap 相同，在已有的层数上增加
+func AddCallerSkip(skip int) Option {
+	return callerSkipOption{zap.AddCallerSkip(skip)}
+}
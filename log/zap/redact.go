@@ -0,0 +1,609 @@
+package zap
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// RedactedValue 敏感信息被隐藏后的默认值
+const RedactedValue = "******"
+
+// RedactRules 敏感信息脱敏规则
+type RedactRules struct {
+	// Keys 敏感字段的键名，忽略大小写以及 "-"、"_"，键名包含其中任意一项即视为敏感字段，
+	// 如 token 会匹配 accessToken、refresh_token，敏感字段的值会被整体替换为 Mask
+	Keys []string
+	// Patterns 字符串值中需要隐藏的内容，对日志消息以及所有字段（包括嵌套对象、JSON 字符串）中的字符串生效
+	Patterns []RedactPattern
+	// Mask 敏感字段的值被替换后的内容，默认为 RedactedValue
+	Mask string
+}
+
+// RedactPattern 按照正则表达式隐藏字符串中的敏感内容
+type RedactPattern struct {
+	Regexp *regexp.Regexp
+	// Replacement 匹配内容被替换后的内容，默认为 RedactRules.Mask
+	Replacement string
+	// Validate 不为 nil 时仅替换通过校验的匹配内容，用于减少误判
+	Validate func(match string) bool
+}
+
+// DefaultRedactKeys 默认的敏感字段键名
+var DefaultRedactKeys = []string{"password", "passwd", "token", "secret", "authorization", "cookie", "set-cookie", "api-key"}
+
+var (
+	// RedactCreditCard 信用卡号，通过 Luhn 算法校验以避免误判时间戳等数字
+	RedactCreditCard = RedactPattern{
+		Regexp:   regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`),
+		Validate: luhn,
+	}
+	// RedactBearerToken HTTP Authorization 头中的 Bearer Token
+	RedactBearerToken = RedactPattern{
+		Regexp:      regexp.MustCompile(`(?i)\bbearer\s+[a-z0-9\-._~+/]+=*`),
+		Replacement: "Bearer " + RedactedValue,
+	}
+	// RedactEmail 电子邮件地址
+	RedactEmail = RedactPattern{
+		Regexp: regexp.MustCompile(`[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}`),
+	}
+)
+
+// DefaultRedactRules 返回默认的脱敏规则，包含 DefaultRedactKeys 以及信用卡号、Bearer Token、电子邮件地址
+func DefaultRedactRules() RedactRules {
+	return RedactRules{
+		Keys:     append([]string(nil), DefaultRedactKeys...),
+		Patterns: []RedactPattern{RedactCreditCard, RedactBearerToken, RedactEmail},
+	}
+}
+
+// WithRedaction 对 New、NewTee、LogConfig.Build 创建的每个输出按照 rules 隐藏敏感信息，
+// 通过 Logger.WithOptions 传入时对子 Logger 的所有输出生效
+func WithRedaction(rules RedactRules) Option {
+	r := NewRedactor(rules)
+	return newCoreOption(func(c *encoderConfig) {
+		c.redactor = r
+	}, func(core zapcore.Core) zapcore.Core {
+		return &redactCore{Core: core, r: r}
+	})
+}
+
+// NewRedactCore 创建按照 rules 隐藏敏感信息的 Core，写入哪些输出仍由 core 决定，因此可以包装 zapcore.NewTee 创建的 Core
+func NewRedactCore(core zapcore.Core, rules RedactRules) zapcore.Core {
+	return &redactCore{Core: core, r: NewRedactor(rules)}
+}
+
+type redactCore struct {
+	zapcore.Core
+	r *Redactor
+}
+
+func (c *redactCore) With(fields []Field) zapcore.Core {
+	return &redactCore{Core: c.Core.With(c.r.fields(fields)), r: c.r}
+}
+
+// Check 由被包装的 Core 检查需要写入的输出，写入时隐藏敏感信息后再写入这些输出
+func (c *redactCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	inner := c.Core.Check(ent, nil)
+	if inner == nil {
+		return ce
+	}
+	w := &redactWriter{inner: inner, r: c.r}
+	ce = ce.AddCore(ent, w)
+	w.outer = ce
+	return ce
+}
+
+func (c *redactCore) Write(ent zapcore.Entry, fields []Field) error {
+	ent.Message = c.r.string(ent.Message)
+	return c.Core.Write(ent, c.r.fields(fields))
+}
+
+// redactWriter 隐藏敏感信息后写入被包装的 Core 检查得到的 CheckedEntry，每次 Check 创建一个，只会写入一次
+type redactWriter struct {
+	inner *zapcore.CheckedEntry
+	outer *zapcore.CheckedEntry // Logger 在 Check 之后才会设置调用位置、ErrorOutput 等信息
+	r     *Redactor
+}
+
+func (w *redactWriter) Enabled(Level) bool        { return true }
+func (w *redactWriter) With([]Field) zapcore.Core { return w }
+func (w *redactWriter) Sync() error               { return nil }
+
+func (w *redactWriter) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, w)
+}
+
+func (w *redactWriter) Write(ent zapcore.Entry, fields []Field) error {
+	ent.Message = w.r.string(ent.Message)
+	w.inner.Entry = ent
+	w.inner.ErrorOutput = w.outer.ErrorOutput
+	w.inner.Write(w.r.fields(fields)...)
+	return nil
+}
+
+// Redactor 按照脱敏规则隐藏敏感信息，可用于在写入日志前自行处理字段，如 HTTP 请求头、请求体
+type Redactor struct {
+	keys     [][]byte // 小写并移除 "-"、"_" 后的键名
+	patterns []RedactPattern
+	mask     string
+}
+
+// NewRedactor 创建按照 rules 隐藏敏感信息的 Redactor
+func NewRedactor(rules RedactRules) *Redactor {
+	r := &Redactor{mask: rules.Mask}
+	if r.mask == "" {
+		r.mask = RedactedValue
+	}
+	for _, k := range rules.Keys {
+		if k := normalizeKey(nil, k); len(k) > 0 {
+			r.keys = append(r.keys, k)
+		}
+	}
+	for _, p := range rules.Patterns {
+		if p.Regexp == nil {
+			continue
+		}
+		if p.Replacement == "" {
+			p.Replacement = r.mask
+		}
+		r.patterns = append(r.patterns, p)
+	}
+	return r
+}
+
+// Sensitive 判断键名是否为敏感字段
+func (r *Redactor) Sensitive(key string) bool {
+	return r.sensitive(key)
+}
+
+// RedactString 隐藏字符串中的敏感内容
+func (r *Redactor) RedactString(s string) string {
+	return r.string(s)
+}
+
+// RedactFields 返回隐藏敏感信息后的字段，不会修改 fields
+func (r *Redactor) RedactFields(fields ...Field) []Field {
+	return r.fields(fields)
+}
+
+// Mask 返回敏感字段的值被替换后的内容
+func (r *Redactor) Mask() string {
+	return r.mask
+}
+
+func normalizeKey(dst []byte, key string) []byte {
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		switch {
+		case c == '-' || c == '_':
+			continue
+		case 'A' <= c && c <= 'Z':
+			c += 'a' - 'A'
+		}
+		dst = append(dst, c)
+	}
+	return dst
+}
+
+// sensitive 判断键名是否为敏感字段
+func (r *Redactor) sensitive(key string) bool {
+	if len(r.keys) == 0 || key == "" {
+		return false
+	}
+	var buf [64]byte
+	k := normalizeKey(buf[:0], key)
+	for _, s := range r.keys {
+		if bytes.Contains(k, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactJSON 解析 JSON 后按照键名以及值隐藏其中的敏感信息，s 不是完整合法的 JSON 时返回 false
+func (r *Redactor) RedactJSON(s string) (string, bool) {
+	var v interface{}
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	if dec.Decode(&v) != nil || dec.More() {
+		return "", false
+	}
+	if v, changed := r.value(v); changed {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", false
+		}
+		return string(data), true
+	}
+	return s, true
+}
+
+// string 隐藏字符串中的敏感内容，JSON 格式的字符串会被解析后按照键名以及值分别处理
+func (r *Redactor) string(s string) string {
+	if looksLikeJSON(s) {
+		if rs, ok := r.RedactJSON(s); ok {
+			return rs
+		}
+	}
+	for _, p := range r.patterns {
+		if !p.Regexp.MatchString(s) {
+			continue
+		}
+		if p.Validate == nil {
+			s = p.Regexp.ReplaceAllLiteralString(s, p.Replacement)
+			continue
+		}
+		s = p.Regexp.ReplaceAllStringFunc(s, func(m string) string {
+			if p.Validate(m) {
+				return p.Replacement
+			}
+			return m
+		})
+	}
+	return s
+}
+
+func looksLikeJSON(s string) bool {
+	s = strings.TrimSpace(s)
+	return len(s) > 1 && (s[0] == '{' && s[len(s)-1] == '}' || s[0] == '[' && s[len(s)-1] == ']')
+}
+
+// value 隐藏 JSON 解码得到的值中的敏感信息
+func (r *Redactor) value(v interface{}) (interface{}, bool) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		changed := false
+		for k, e := range v {
+			if r.sensitive(k) {
+				v[k], changed = r.mask, true
+			} else if e, ok := r.value(e); ok {
+				v[k], changed = e, true
+			}
+		}
+		return v, changed
+	case []interface{}:
+		changed := false
+		for i, e := range v {
+			if e, ok := r.value(e); ok {
+				v[i], changed = e, true
+			}
+		}
+		return v, changed
+	case string:
+		s := r.string(v)
+		return s, s != v
+	}
+	return v, false
+}
+
+// reflected 通过 JSON 编码处理任意类型的值，值中没有敏感信息时返回 false
+func (r *Redactor) reflected(v interface{}) (interface{}, bool) {
+	switch v.(type) {
+	case nil, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return v, false
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v, false
+	}
+	var tree interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err = dec.Decode(&tree); err != nil {
+		return v, false
+	}
+	return r.value(tree)
+}
+
+// fields 返回隐藏敏感信息后的字段，不会修改 fields
+func (r *Redactor) fields(fields []Field) []Field {
+	var out []Field
+	for i, f := range fields {
+		rf, changed := r.field(f)
+		if !changed {
+			if out != nil {
+				out = append(out, f)
+			}
+			continue
+		}
+		if out == nil {
+			out = make([]Field, i, len(fields))
+			copy(out, fields[:i])
+		}
+		out = append(out, rf)
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+func (r *Redactor) field(f Field) (Field, bool) {
+	switch f.Type {
+	case zapcore.SkipType, zapcore.NamespaceType:
+		return f, false
+	}
+	if r.sensitive(f.Key) {
+		return zap.String(f.Key, r.mask), true
+	}
+
+	switch f.Type {
+	case zapcore.StringType:
+		if s := r.string(f.String); s != f.String {
+			return zap.String(f.Key, s), true
+		}
+	case zapcore.ByteStringType:
+		b, _ := f.Interface.([]byte)
+		if s := r.string(string(b)); s != string(b) {
+			return zap.ByteString(f.Key, []byte(s)), true
+		}
+	case zapcore.StringerType, zapcore.ErrorType:
+		// 仅在包含敏感信息时才将其转换为字符串字段，避免丢失 errorVerbose 等信息
+		if s, ok := safeString(f.Interface); ok {
+			if rs := r.string(s); rs != s {
+				return zap.String(f.Key, rs), true
+			}
+		}
+	case zapcore.ReflectType:
+		if v, changed := r.reflected(f.Interface); changed {
+			return zap.Reflect(f.Key, v), true
+		}
+	case zapcore.ObjectMarshalerType, zapcore.InlineMarshalerType:
+		f.Interface = redactObject{m: f.Interface.(zapcore.ObjectMarshaler), r: r}
+		return f, true
+	case zapcore.ArrayMarshalerType:
+		f.Interface = redactArray{m: f.Interface.(zapcore.ArrayMarshaler), r: r}
+		return f, true
+	}
+	return f, false
+}
+
+// safeString 获取 fmt.Stringer 或 error 的字符串，调用时 panic（如 nil 指针）返回 false
+func safeString(v interface{}) (s string, ok bool) {
+	defer func() {
+		if recover() != nil {
+			s, ok = "", false
+		}
+	}()
+	switch v := v.(type) {
+	case error:
+		return v.Error(), true
+	case fmt.Stringer:
+		return v.String(), true
+	}
+	return "", false
+}
+
+// redactObject 在编码嵌套对象时隐藏敏感信息
+type redactObject struct {
+	m zapcore.ObjectMarshaler
+	r *Redactor
+}
+
+func (o redactObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	return o.m.MarshalLogObject(redactEncoder{ObjectEncoder: enc, r: o.r})
+}
+
+type redactArray struct {
+	m zapcore.ArrayMarshaler
+	r *Redactor
+}
+
+func (a redactArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	return a.m.MarshalLogArray(redactArrayEncoder{ArrayEncoder: enc, r: a.r})
+}
+
+// redactEncoder 隐藏敏感字段的值，以及字符串中的敏感内容
+type redactEncoder struct {
+	zapcore.ObjectEncoder
+	r *Redactor
+}
+
+func (e redactEncoder) mask(key string) bool {
+	if e.r.sensitive(key) {
+		e.ObjectEncoder.AddString(key, e.r.mask)
+		return true
+	}
+	return false
+}
+
+func (e redactEncoder) AddArray(key string, m zapcore.ArrayMarshaler) error {
+	if e.mask(key) {
+		return nil
+	}
+	return e.ObjectEncoder.AddArray(key, redactArray{m: m, r: e.r})
+}
+
+func (e redactEncoder) AddObject(key string, m zapcore.ObjectMarshaler) error {
+	if e.mask(key) {
+		return nil
+	}
+	return e.ObjectEncoder.AddObject(key, redactObject{m: m, r: e.r})
+}
+
+func (e redactEncoder) AddReflected(key string, v interface{}) error {
+	if e.mask(key) {
+		return nil
+	}
+	v, _ = e.r.reflected(v)
+	return e.ObjectEncoder.AddReflected(key, v)
+}
+
+func (e redactEncoder) AddBinary(key string, v []byte) {
+	if !e.mask(key) {
+		e.ObjectEncoder.AddBinary(key, v)
+	}
+}
+
+func (e redactEncoder) AddByteString(key string, v []byte) {
+	if !e.mask(key) {
+		e.ObjectEncoder.AddString(key, e.r.string(string(v)))
+	}
+}
+
+func (e redactEncoder) AddString(key, v string) {
+	if !e.mask(key) {
+		e.ObjectEncoder.AddString(key, e.r.string(v))
+	}
+}
+
+func (e redactEncoder) AddInt(key string, v int) {
+	if !e.mask(key) {
+		e.ObjectEncoder.AddInt(key, v)
+	}
+}
+
+func (e redactEncoder) AddInt64(key string, v int64) {
+	if !e.mask(key) {
+		e.ObjectEncoder.AddInt64(key, v)
+	}
+}
+
+func (e redactEncoder) AddUint(key string, v uint) {
+	if !e.mask(key) {
+		e.ObjectEncoder.AddUint(key, v)
+	}
+}
+
+func (e redactEncoder) AddUint64(key string, v uint64) {
+	if !e.mask(key) {
+		e.ObjectEncoder.AddUint64(key, v)
+	}
+}
+
+func (e redactEncoder) AddFloat64(key string, v float64) {
+	if !e.mask(key) {
+		e.ObjectEncoder.AddFloat64(key, v)
+	}
+}
+
+func (e redactEncoder) AddDuration(key string, v time.Duration) {
+	if !e.mask(key) {
+		e.ObjectEncoder.AddDuration(key, v)
+	}
+}
+
+func (e redactEncoder) AddBool(key string, v bool) {
+	if !e.mask(key) {
+		e.ObjectEncoder.AddBool(key, v)
+	}
+}
+
+func (e redactEncoder) AddComplex128(key string, v complex128) {
+	if !e.mask(key) {
+		e.ObjectEncoder.AddComplex128(key, v)
+	}
+}
+
+func (e redactEncoder) AddComplex64(key string, v complex64) {
+	if !e.mask(key) {
+		e.ObjectEncoder.AddComplex64(key, v)
+	}
+}
+
+func (e redactEncoder) AddFloat32(key string, v float32) {
+	if !e.mask(key) {
+		e.ObjectEncoder.AddFloat32(key, v)
+	}
+}
+
+func (e redactEncoder) AddInt32(key string, v int32) {
+	if !e.mask(key) {
+		e.ObjectEncoder.AddInt32(key, v)
+	}
+}
+
+func (e redactEncoder) AddInt16(key string, v int16) {
+	if !e.mask(key) {
+		e.ObjectEncoder.AddInt16(key, v)
+	}
+}
+
+func (e redactEncoder) AddInt8(key string, v int8) {
+	if !e.mask(key) {
+		e.ObjectEncoder.AddInt8(key, v)
+	}
+}
+
+func (e redactEncoder) AddTime(key string, v time.Time) {
+	if !e.mask(key) {
+		e.ObjectEncoder.AddTime(key, v)
+	}
+}
+
+func (e redactEncoder) AddUint32(key string, v uint32) {
+	if !e.mask(key) {
+		e.ObjectEncoder.AddUint32(key, v)
+	}
+}
+
+func (e redactEncoder) AddUint16(key string, v uint16) {
+	if !e.mask(key) {
+		e.ObjectEncoder.AddUint16(key, v)
+	}
+}
+
+func (e redactEncoder) AddUint8(key string, v uint8) {
+	if !e.mask(key) {
+		e.ObjectEncoder.AddUint8(key, v)
+	}
+}
+
+func (e redactEncoder) AddUintptr(key string, v uintptr) {
+	if !e.mask(key) {
+		e.ObjectEncoder.AddUintptr(key, v)
+	}
+}
+
+type redactArrayEncoder struct {
+	zapcore.ArrayEncoder
+	r *Redactor
+}
+
+func (e redactArrayEncoder) AppendArray(m zapcore.ArrayMarshaler) error {
+	return e.ArrayEncoder.AppendArray(redactArray{m: m, r: e.r})
+}
+
+func (e redactArrayEncoder) AppendObject(m zapcore.ObjectMarshaler) error {
+	return e.ArrayEncoder.AppendObject(redactObject{m: m, r: e.r})
+}
+
+func (e redactArrayEncoder) AppendReflected(v interface{}) error {
+	v, _ = e.r.reflected(v)
+	return e.ArrayEncoder.AppendReflected(v)
+}
+
+func (e redactArrayEncoder) AppendByteString(v []byte) {
+	e.ArrayEncoder.AppendString(e.r.string(string(v)))
+}
+
+func (e redactArrayEncoder) AppendString(v string) {
+	e.ArrayEncoder.AppendString(e.r.string(v))
+}
+
+// luhn 使用 Luhn 算法校验卡号，忽略其中的空格以及 "-"
+func luhn(s string) bool {
+	var sum, n int
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && sum%10 == 0
+}
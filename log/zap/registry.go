@@ -0,0 +1,301 @@
+package zap
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// levelRegistry 同一个 Logger 及其子 Logger 共用的日志级别，
+// 包括默认日志级别以及按名称为子 Logger 单独设置的日志级别
+type levelRegistry struct {
+	al *zap.AtomicLevel // 默认日志级别，为 nil 时不限制
+
+	mu    sync.Mutex // 保护 names 的写入
+	names atomic.Pointer[nameLevels]
+
+	revertMu sync.Mutex
+	reverts  map[string]*revert // 等待自动恢复的修改
+}
+
+// nameLevels 按名称设置的日志级别，写时复制
+type nameLevels struct {
+	levels   map[string]Level // 名称或者通配符模式
+	patterns []string         // 包含通配符的模式，按长度从长到短排序
+	min      Level            // 所有名称中最低的日志级别
+	cache    sync.Map         // 名称 => lookupResult
+}
+
+type lookupResult struct {
+	level Level
+	ok    bool
+}
+
+func newLevelRegistry(al *zap.AtomicLevel) *levelRegistry {
+	r := &levelRegistry{al: al}
+	r.names.Store(&nameLevels{})
+	return r
+}
+
+// lookup 查找名称对应的日志级别，名称本身以及上级名称（如 db.query => db）都会参与匹配，
+// 匹配的名称或者通配符模式越长越优先，长度相同时完全相同的名称优先
+func (n *nameLevels) lookup(name string) (Level, bool) {
+	if v, ok := n.cache.Load(name); ok {
+		r := v.(lookupResult)
+		return r.level, r.ok
+	}
+	level, ok := n.find(name)
+	n.cache.Store(name, lookupResult{level: level, ok: ok})
+	return level, ok
+}
+
+func (n *nameLevels) find(name string) (level Level, ok bool) {
+	best := -1
+	for name != "" {
+		if l, found := n.levels[name]; found && 2*len(name)+1 > best {
+			level, ok, best = l, true, 2*len(name)+1
+		}
+		for _, p := range n.patterns {
+			if 2*len(p) <= best {
+				break
+			}
+			if matched, _ := path.Match(p, name); matched {
+				level, ok, best = n.levels[p], true, 2*len(p)
+				break
+			}
+		}
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+	return level, ok
+}
+
+// isPattern 名称是否包含通配符
+func isPattern(name string) bool {
+	return strings.ContainsAny(name, "*?[\\")
+}
+
+// validatePattern 校验名称或者通配符模式，通配符语法与 path.Match 相同
+func validatePattern(name string) error {
+	if name == "" {
+		return errors.New("empty logger name")
+	}
+	if _, err := path.Match(name, ""); err != nil {
+		return fmt.Errorf("invalid logger name pattern %q: %w", name, err)
+	}
+	return nil
+}
+
+func (r *levelRegistry) defaultEnabled(level Level) bool {
+	return r.al == nil || r.al.Enabled(level)
+}
+
+// enabled 名称为 name 的 Logger 是否启用 level 级别的日志
+func (r *levelRegistry) enabled(name string, level Level) bool {
+	n := r.names.Load()
+	if len(n.levels) > 0 {
+		if l, ok := n.lookup(name); ok {
+			return level >= l
+		}
+	}
+	return r.defaultEnabled(level)
+}
+
+// anyEnabled 是否有任意名称的 Logger 启用 level 级别的日志
+func (r *levelRegistry) anyEnabled(level Level) bool {
+	n := r.names.Load()
+	return len(n.levels) > 0 && level >= n.min || r.defaultEnabled(level)
+}
+
+func (r *levelRegistry) update(fn func(levels map[string]Level)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	old := r.names.Load()
+	levels := make(map[string]Level, len(old.levels)+1)
+	for k, v := range old.levels {
+		levels[k] = v
+	}
+	fn(levels)
+	n := &nameLevels{levels: levels, min: FatalLevel}
+	for name, l := range levels {
+		if l < n.min {
+			n.min = l
+		}
+		if isPattern(name) {
+			n.patterns = append(n.patterns, name)
+		}
+	}
+	sort.Slice(n.patterns, func(i, j int) bool {
+		pi, pj := n.patterns[i], n.patterns[j]
+		return len(pi) > len(pj) || len(pi) == len(pj) && pi < pj
+	})
+	r.names.Store(n)
+}
+
+func (r *levelRegistry) set(name string, level Level) {
+	r.update(func(levels map[string]Level) { levels[name] = level })
+}
+
+// replace 替换所有按名称设置的日志级别
+func (r *levelRegistry) replace(named map[string]Level) {
+	r.update(func(levels map[string]Level) {
+		for k := range levels {
+			delete(levels, k)
+		}
+		for k, v := range named {
+			levels[k] = v
+		}
+	})
+}
+
+func (r *levelRegistry) reset(name string) {
+	r.update(func(levels map[string]Level) { delete(levels, name) })
+}
+
+func (r *levelRegistry) levels() map[string]Level {
+	n := r.names.Load()
+	levels := make(map[string]Level, len(n.levels))
+	for k, v := range n.levels {
+		levels[k] = v
+	}
+	return levels
+}
+
+// levelCore 根据日志条目的 Logger 名称判断是否启用，内部的 Core 只需要处理输出的日志级别范围
+type levelCore struct {
+	zapcore.Core
+	reg *levelRegistry
+}
+
+func (c *levelCore) Enabled(level Level) bool {
+	return c.reg.anyEnabled(level) && c.Core.Enabled(level)
+}
+
+func (c *levelCore) With(fields []Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), reg: c.reg}
+}
+
+func (c *levelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.reg.enabled(ent.LoggerName, ent.Level) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
+
+// SetNamedLevel 为名称为 name 的子 Logger（通过 Named 创建）单独设置日志级别，
+// 同样对其下级子 Logger 生效，如设置 db 同时会影响 db.query
+// name 可以是 path.Match 语法的通配符模式，如 db.*、http.?lient，名称不合法时不做修改
+func (l *Logger) SetNamedLevel(name string, level Level) {
+	if validatePattern(name) == nil {
+		l.levels.set(name, level)
+	}
+}
+
+// ResetNamedLevel 移除为名称为 name 的子 Logger 单独设置的日志级别，恢复使用默认日志级别
+func (l *Logger) ResetNamedLevel(name string) {
+	l.levels.reset(name)
+}
+
+// NamedLevels 返回所有按名称单独设置的日志级别
+func (l *Logger) NamedLevels() map[string]Level {
+	return l.levels.levels()
+}
+
+// Level 返回默认日志级别，对于使用 NewTee 创建的 Logger 返回 DebugLevel
+func (l *Logger) Level() Level {
+	if l.al == nil {
+		return DebugLevel
+	}
+	return l.al.Level()
+}
+
+// ParseLevels 解析 "info,db.*=debug,http=warn" 格式的日志级别配置，
+// 不带名称的项为默认日志级别（def 为 nil 表示未设置），其他项为按名称或者通配符模式设置的日志级别
+func ParseLevels(spec string) (def *Level, named map[string]Level, err error) {
+	named = make(map[string]Level)
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, text, ok := strings.Cut(item, "=")
+		if !ok {
+			name, text = "", item
+		}
+		name = strings.TrimSpace(name)
+		var level Level
+		if err = level.UnmarshalText([]byte(strings.TrimSpace(text))); err != nil {
+			return nil, nil, fmt.Errorf("parse log levels %q: %w", item, err)
+		}
+		if !ok {
+			if def != nil {
+				return nil, nil, fmt.Errorf("parse log levels %q: duplicate default level", spec)
+			}
+			def = &level
+			continue
+		}
+		if err = validatePattern(name); err != nil {
+			return nil, nil, fmt.Errorf("parse log levels %q: %w", item, err)
+		}
+		named[name] = level
+	}
+	return def, named, nil
+}
+
+// SetLevels 按照 ParseLevels 格式的配置设置日志级别，如 "info,db.*=debug,http=warn"
+// 会替换所有按名称设置的日志级别，配置中没有默认日志级别时保持默认日志级别不变
+func (l *Logger) SetLevels(spec string) error {
+	def, named, err := ParseLevels(spec)
+	if err != nil {
+		return err
+	}
+	if def != nil {
+		if l.al == nil {
+			return errors.New("logger created by NewTee has no default level")
+		}
+		l.SetLevel(*def)
+	}
+	l.levels.replace(named)
+	return nil
+}
+
+// LevelSpec 返回当前的日志级别配置，格式与 SetLevels 相同
+func (l *Logger) LevelSpec() string {
+	var items []string
+	if l.al != nil {
+		items = append(items, l.al.Level().String())
+	}
+	named := l.NamedLevels()
+	for _, name := range sortedNames(named) {
+		items = append(items, name+"="+named[name].String())
+	}
+	return strings.Join(items, ",")
+}
+
+func SetLevels(spec string) error { return std.SetLevels(spec) }
+func LevelSpec() string           { return std.LevelSpec() }
+
+func SetNamedLevel(name string, level Level) { std.SetNamedLevel(name, level) }
+func ResetNamedLevel(name string)            { std.ResetNamedLevel(name) }
+func NamedLevels() map[string]Level          { return std.NamedLevels() }
+func GetLevel() Level                        { return std.Level() }
+
+func sortedNames[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,97 @@
+package zap
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLevels(t *testing.T) {
+	def, named, err := ParseLevels("info, db.*=debug,http=WARN,,")
+	require.NoError(t, err)
+	assert.Equal(t, InfoLevel, *def)
+	assert.Equal(t, map[string]Level{"db.*": DebugLevel, "http": WarnLevel}, named)
+
+	def, named, err = ParseLevels("db=error")
+	require.NoError(t, err)
+	assert.Nil(t, def)
+	assert.Equal(t, map[string]Level{"db": ErrorLevel}, named)
+
+	for spec, msg := range map[string]string{
+		"info,debug": `parse log levels "info,debug": duplicate default level`,
+		"db=verbose": `parse log levels "db=verbose": unrecognized level: "verbose"`,
+		"=debug":     `parse log levels "=debug": empty logger name`,
+		"db.[=debug": `parse log levels "db.[=debug": invalid logger name pattern "db.[": syntax error in pattern`,
+	} {
+		_, _, err = ParseLevels(spec)
+		assert.EqualError(t, err, msg, spec)
+	}
+}
+
+func TestNamedLevelPatterns(t *testing.T) {
+	l := New(&bytes.Buffer{}, InfoLevel)
+	require.NoError(t, l.SetLevels("info,db=error,db.*=debug,db.slow=error,http=warn,http.client=debug,*.cache=error"))
+	assert.Equal(t, "info,*.cache=error,db=error,db.*=debug,db.slow=error,http=warn,http.client=debug", l.LevelSpec())
+
+	tests := []struct {
+		name  string
+		level Level
+	}{
+		{"", InfoLevel},
+		{"app", InfoLevel},
+		{"db", ErrorLevel},               // db.* 不匹配 db 本身
+		{"db.query", DebugLevel},         // 通配符模式比上级名称更长
+		{"db.query.rows", DebugLevel},    // 通配符模式比上级名称更长
+		{"db.slow", ErrorLevel},          // 完全相同的名称比通配符模式更长
+		{"db.slow.index", ErrorLevel},    // 上级名称比通配符模式更长
+		{"db.cache", ErrorLevel},         // 更长的通配符模式优先
+		{"http", WarnLevel},              // 完全相同的名称
+		{"http.server", WarnLevel},       // 上级名称
+		{"http.client", DebugLevel},      // 完全相同的名称
+		{"http.client.pool", DebugLevel}, // 上级名称
+		{"redis.cache", ErrorLevel},      // 通配符模式
+	}
+	for _, tt := range tests {
+		for _, level := range []Level{DebugLevel, InfoLevel, WarnLevel, ErrorLevel} {
+			assert.Equal(t, level >= tt.level, l.levels.enabled(tt.name, level), "%s %s", tt.name, level)
+		}
+	}
+
+	// 运行时修改
+	l.SetNamedLevel("db.*", WarnLevel)
+	assert.False(t, l.levels.enabled("db.query", InfoLevel))
+	require.NoError(t, l.SetLevels("error"))
+	assert.Equal(t, "error", l.LevelSpec())
+	assert.False(t, l.levels.enabled("db.query", WarnLevel))
+}
+
+func TestNamedLoggerLevels(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf, InfoLevel, WithKeyNames(KeyNames{Time: "-"}))
+	require.NoError(t, l.SetLevels("db.*=debug"))
+
+	db := l.Named("db")
+	db.Named("query").Debug("query debug")
+	db.Debug("db debug")
+	l.Named("http").With(String("k", "v")).Debug("http debug")
+
+	assert.Equal(t, `{"level":"debug","logger":"db.query","msg":"query debug"}
+`, buf.String())
+	assert.EqualError(t, NewTee(nil).SetLevels("info"), "logger created by NewTee has no default level")
+}
+
+func TestLogConfigLoggers(t *testing.T) {
+	cfg := &LogConfig{Loggers: map[string]Level{"db.*": DebugLevel}}
+	l, err := cfg.Build()
+	require.NoError(t, err)
+	assert.Equal(t, "info,db.*=debug", l.LevelSpec())
+
+	cfg.Loggers = map[string]Level{"http": WarnLevel}
+	l.ApplyConfig(cfg)
+	assert.Equal(t, "info,http=warn", l.LevelSpec())
+
+	cfg.Loggers = map[string]Level{"db.[": DebugLevel}
+	assert.Error(t, cfg.Validate())
+}
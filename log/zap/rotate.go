@@ -2,6 +2,7 @@ package zap
 
 import (
 	"io"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -48,21 +49,28 @@ func NewProductionRotateConfig(filename string) *RotateConfig {
 	}
 }
 
+// NewRotateByTime 创建按时间轮转的 io.Writer，轮转的文件名为在扩展名之前插入时间，如 app.2023-03-19-21-57-59.log
+// 文件名不合法时 panic，需要处理错误时请使用 LogConfig.Build
 func NewRotateByTime(cfg *RotateConfig) io.Writer {
+	l, err := newRotateByTime(cfg)
+	if err != nil {
+		panic(err)
+	}
+	return l
+}
+
+func newRotateByTime(cfg *RotateConfig) (*rotatelogs.RotateLogs, error) {
 	opts := []rotatelogs.Option{
 		rotatelogs.WithMaxAge(time.Duration(cfg.MaxAge) * time.Hour * 24),
 		rotatelogs.WithRotationTime(cfg.RotationTime),
 		rotatelogs.WithLinkName(cfg.Filename),
 	}
 	if !cfg.LocalTime {
-		rotatelogs.WithClock(rotatelogs.UTC)
+		opts = append(opts, rotatelogs.WithClock(rotatelogs.UTC))
 	}
-	filename := strings.SplitN(cfg.Filename, ".", 2)
-	l, _ := rotatelogs.New(
-		filename[0]+".%Y-%m-%d-%H-%M-%S."+filename[1],
-		opts...,
-	)
-	return l
+	// 只按照文件名（不包括目录）的扩展名拆分，避免 ./logs/app.log 这样的路径被错误拆分
+	ext := filepath.Ext(cfg.Filename)
+	return rotatelogs.New(strings.TrimSuffix(cfg.Filename, ext)+".%Y-%m-%d-%H-%M-%S"+ext, opts...)
 }
 
 func NewRotateBySize(cfg *RotateConfig) io.Writer {
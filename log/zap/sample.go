@@ -0,0 +1,204 @@
+package zap
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// WithSampling 对日志进行采样，每个 tick 内相同级别、相同消息的日志只记录前 first 条，之后每 thereafter 条记录一条，
+// thereafter 为 0 时丢弃之后的所有日志，tick 默认为 1s
+// 采样在按名称设置的日志级别之后进行，对所有输出生效
+func WithSampling(tick time.Duration, first, thereafter int) Option {
+	sc := &SamplingConfig{Tick: ConfigDuration(tick), Initial: first, Thereafter: thereafter}
+	return newCoreOption(func(c *encoderConfig) {
+		c.sampling = sc
+	}, func(core zapcore.Core) zapcore.Core {
+		return zapcore.NewSamplerWithOptions(core, sc.tick(), first, thereafter)
+	})
+}
+
+// WithDedupe 合并 window 时间窗口内重复的日志，参考 NewDedupeCore，window 不大于 0 时不合并
+// 注意：是否重复只比较级别、Logger 名称以及消息，不比较字段，字段不同的日志也会被合并，
+// 合并后只保留第一条日志的字段，需要按字段区分的日志应将区分的内容写入消息中
+func WithDedupe(window time.Duration) Option {
+	return newCoreOption(func(c *encoderConfig) {
+		c.dedupe = window
+	}, func(core zapcore.Core) zapcore.Core {
+		if window <= 0 {
+			return core
+		}
+		return NewDedupeCore(core, window)
+	})
+}
+
+// RepeatedKey 合并重复日志后记录重复次数的字段
+const RepeatedKey = "repeated"
+
+// NewDedupeCore 创建合并重复日志的 Core，相同级别、相同 Logger 名称、相同消息的日志视为重复日志，不比较字段
+// DPanic、Panic、Fatal 级别的日志不会被合并
+// 时间窗口内第一条日志会立即写入，之后重复的日志会被合并，在时间窗口结束或者调用 Sync 时写入一条
+// 带有 repeated: N 字段的日志，N 为被合并的日志条数，字段为第一条日志的字段
+func NewDedupeCore(core zapcore.Core, window time.Duration) zapcore.Core {
+	return &dedupeCore{Core: core, d: &deduper{window: window, entries: make(map[dedupeKey]*dedupeEntry)}}
+}
+
+type dedupeCore struct {
+	zapcore.Core
+	d *deduper
+}
+
+type deduper struct {
+	window time.Duration
+
+	mu        sync.Mutex
+	entries   map[dedupeKey]*dedupeEntry
+	lastSweep time.Time
+}
+
+type dedupeKey struct {
+	level   Level
+	logger  string
+	message string
+}
+
+type dedupeEntry struct {
+	core     zapcore.Core  // 写入第一条日志的 Core，合并后的日志同样通过它写入
+	ent      zapcore.Entry // 第一条日志
+	last     time.Time     // 最后一条重复日志的时间
+	fields   []Field
+	repeated int
+	timer    *time.Timer
+}
+
+func (c *dedupeCore) With(fields []Field) zapcore.Core {
+	return &dedupeCore{Core: c.Core.With(fields), d: c.d}
+}
+
+func (c *dedupeCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(ent.Level) {
+		return ce
+	}
+	if ent.Level >= zapcore.DPanicLevel {
+		// DPanic、Panic、Fatal 级别的日志不合并，保证日志写入后 panic 或者退出
+		return c.Core.Check(ent, ce)
+	}
+	key := dedupeKey{level: ent.Level, logger: ent.LoggerName, message: ent.Message}
+
+	d := c.d
+	d.mu.Lock()
+	d.sweep(ent.Time)
+	e, ok := d.entries[key]
+	if ok && ent.Time.Sub(e.ent.Time) < d.window {
+		e.repeated++
+		e.last = ent.Time
+		if e.timer == nil {
+			e.timer = time.AfterFunc(d.window-ent.Time.Sub(e.ent.Time), func() { d.flush(key, e) })
+		}
+		d.mu.Unlock()
+		return ce
+	}
+	expired := ok && d.remove(key, e)
+	first := &dedupeEntry{core: c.Core, ent: ent}
+	d.entries[key] = first
+	d.mu.Unlock()
+
+	if expired {
+		d.write(e)
+	}
+	if ce = c.Core.Check(ent, ce); ce == nil {
+		return nil
+	}
+	return ce.AddCore(ent, fieldRecorder{d: d, e: first})
+}
+
+func (c *dedupeCore) Sync() error {
+	c.d.flushAll()
+	return c.Core.Sync()
+}
+
+// sweep 每个时间窗口清理一次已经过期且没有重复的日志，调用时需要持有锁
+func (d *deduper) sweep(now time.Time) {
+	if now.Sub(d.lastSweep) < d.window {
+		return
+	}
+	d.lastSweep = now
+	for k, e := range d.entries {
+		if e.repeated == 0 && now.Sub(e.ent.Time) >= d.window {
+			delete(d.entries, k)
+		}
+	}
+}
+
+// remove 移除日志并停止定时器，日志已经被移除时返回 false，调用时需要持有锁
+func (d *deduper) remove(key dedupeKey, e *dedupeEntry) bool {
+	if d.entries[key] != e {
+		return false
+	}
+	delete(d.entries, key)
+	if e.timer != nil {
+		e.timer.Stop()
+	}
+	return true
+}
+
+func (d *deduper) flush(key dedupeKey, e *dedupeEntry) {
+	d.mu.Lock()
+	removed := d.remove(key, e)
+	d.mu.Unlock()
+	if removed {
+		d.write(e)
+	}
+}
+
+func (d *deduper) flushAll() {
+	d.mu.Lock()
+	var pending []*dedupeEntry
+	for k, e := range d.entries {
+		if e.repeated > 0 {
+			d.remove(k, e)
+			pending = append(pending, e)
+		}
+	}
+	d.mu.Unlock()
+	for _, e := range pending {
+		d.write(e)
+	}
+}
+
+// write 写入已经移除的日志合并后的日志
+func (d *deduper) write(e *dedupeEntry) {
+	if e.repeated == 0 {
+		return
+	}
+	ent := e.ent
+	ent.Time = e.last
+	d.mu.Lock()
+	fields := append(e.fields[:len(e.fields):len(e.fields)], Int(RepeatedKey, e.repeated))
+	d.mu.Unlock()
+	if ce := e.core.Check(ent, nil); ce != nil {
+		ce.Write(fields...)
+	}
+}
+
+// fieldRecorder 记录第一条日志的字段
+type fieldRecorder struct {
+	d *deduper
+	e *dedupeEntry
+}
+
+func (r fieldRecorder) Enabled(Level) bool        { return true }
+func (r fieldRecorder) With([]Field) zapcore.Core { return r }
+func (r fieldRecorder) Sync() error               { return nil }
+
+func (r fieldRecorder) Write(_ zapcore.Entry, fields []Field) error {
+	r.d.mu.Lock()
+	r.e.fields = append([]Field(nil), fields...)
+	r.d.mu.Unlock()
+	return nil
+}
+
+func (r fieldRecorder) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, r)
+}
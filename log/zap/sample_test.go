@@ -0,0 +1,146 @@
+package zap
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// manualClock 可以手动调整时间的 zapcore.Clock
+type manualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *manualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *manualClock) NewTicker(d time.Duration) *time.Ticker { return time.NewTicker(d) }
+
+func (c *manualClock) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestWithSampling(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	l := NewTee([]TeeOption{
+		{Out: &stdout, LevelEnablerFunc: func(level Level) bool { return level < WarnLevel }},
+		{Out: &stderr, LevelEnablerFunc: func(level Level) bool { return level >= WarnLevel }},
+	}, WithSampling(time.Minute, 2, 3), WithKeyNames(KeyNames{Time: "-", Level: "-"}))
+
+	for i := 0; i < 10; i++ {
+		l.Info("info", Int("i", i))
+		l.Error("error", Int("i", i))
+	}
+	// 每条消息记录前 2 条，之后每 3 条记录一条
+	assert.Equal(t, `{"msg":"info","i":0}
+{"msg":"info","i":1}
+{"msg":"info","i":4}
+{"msg":"info","i":7}
+`, stdout.String())
+	assert.Equal(t, 4, strings.Count(stderr.String(), `"msg":"error"`))
+}
+
+func TestWithDedupe(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf, InfoLevel, WithDedupe(time.Hour), WithKeyNames(KeyNames{Time: "-", Level: "-"}))
+
+	db := l.Named("db").With(String("addr", "127.0.0.1:3306"))
+	for i := 0; i < 5; i++ {
+		db.Error("dial failed", Any("error", errors.New("connection refused")), Int("i", i))
+	}
+	l.Error("dial failed")
+	db.Warn("dial failed")
+	l.Debug("debug")
+	assert.Equal(t, `{"logger":"db","msg":"dial failed","addr":"127.0.0.1:3306","error":"connection refused","i":0}
+{"msg":"dial failed"}
+{"logger":"db","msg":"dial failed","addr":"127.0.0.1:3306"}
+`, buf.String())
+
+	// Sync 时写入合并后的日志，字段为第一条日志的字段
+	buf.Reset()
+	require.NoError(t, l.Sync())
+	assert.Equal(t, `{"logger":"db","msg":"dial failed","addr":"127.0.0.1:3306","error":"connection refused","i":0,"repeated":4}
+`, buf.String())
+
+	buf.Reset()
+	db.Error("dial failed")
+	require.NoError(t, l.Sync())
+	assert.Equal(t, `{"logger":"db","msg":"dial failed","addr":"127.0.0.1:3306"}
+`, buf.String())
+}
+
+func TestSamplingWithOptions(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf, InfoLevel, WithKeyNames(KeyNames{Time: "-", Level: "-"}))
+
+	sampled := l.WithOptions(WithSampling(time.Minute, 1, 0))
+	deduped := l.WithOptions(WithDedupe(time.Hour))
+	for i := 0; i < 3; i++ {
+		sampled.Info("sampled")
+		deduped.Info("deduped")
+	}
+	require.NoError(t, deduped.Sync())
+	assert.Equal(t, `{"msg":"sampled"}
+{"msg":"deduped"}
+{"msg":"deduped","repeated":2}
+`, buf.String())
+}
+
+func TestDedupeWindow(t *testing.T) {
+	t.Run("expired", func(t *testing.T) {
+		var buf bytes.Buffer
+		clk := &manualClock{now: time.Date(2023, 3, 19, 21, 57, 59, 0, time.UTC)}
+		l := New(&buf, InfoLevel, WithDedupe(time.Hour), WithClock(clk), WithKeyNames(KeyNames{Level: "-"}))
+
+		l.Info("msg")
+		clk.Add(time.Minute)
+		l.Info("msg")
+		l.Info("msg")
+		// 时间窗口结束后再次出现的日志会先写入合并后的日志
+		clk.Add(time.Hour)
+		l.Info("msg")
+		assert.Equal(t, `{"ts":"2023-03-19T21:57:59Z","msg":"msg"}
+{"ts":"2023-03-19T21:58:59Z","msg":"msg","repeated":2}
+{"ts":"2023-03-19T22:58:59Z","msg":"msg"}
+`, buf.String())
+	})
+
+	t.Run("timer", func(t *testing.T) {
+		var buf syncBuffer
+		l := New(&buf, InfoLevel, WithDedupe(20*time.Millisecond), WithKeyNames(KeyNames{Time: "-", Level: "-"}))
+		for i := 0; i < 3; i++ {
+			l.Info("msg")
+		}
+		assert.Eventually(t, func() bool {
+			return buf.String() == `{"msg":"msg"}
+{"msg":"msg","repeated":2}
+`
+		}, time.Second, 5*time.Millisecond)
+	})
+}
+
+func TestDedupePanic(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf, InfoLevel, WithDedupe(time.Hour), WithKeyNames(KeyNames{Time: "-", Level: "-"}))
+
+	// Panic 级别的日志不合并，每次都会写入并 panic
+	for i := 0; i < 2; i++ {
+		assert.Panics(t, func() { l.Panic("panic", Int("i", i)) })
+	}
+	require.NoError(t, l.Sync())
+	assert.Equal(t, `{"msg":"panic","i":0}
+{"msg":"panic","i":1}
+`, buf.String())
+}
@@ -0,0 +1,44 @@
+//go:build unix
+
+package zap
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// HandleLevelSignals 安装信号处理函数，收到 SIGUSR1 时将默认日志级别降低一级（更详细，最低为 Debug），
+// 收到 SIGUSR2 时提高一级（最高为 Error），每次修改都会记录一条日志，调用返回的 stop 函数移除信号处理
+// 与 SetLevel 相同，对于使用 NewTee 创建的 Logger 无效
+func (l *Logger) HandleLevelSignals() (stop func()) {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, syscall.SIGUSR1, syscall.SIGUSR2)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case sig := <-c:
+				if sig == syscall.SIGUSR1 {
+					l.stepLevel(-1, sig)
+				} else {
+					l.stepLevel(1, sig)
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(c)
+			close(done)
+		})
+	}
+}
+
+// HandleLevelSignals 为默认 Logger 安装信号处理函数，ReplaceDefault 之前安装的信号处理函数仍然作用于原来的 Logger
+func HandleLevelSignals() (stop func()) { return std.HandleLevelSignals() }
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package zap
+
+// HandleLevelSignals 当前平台不支持 SIGUSR1、SIGUSR2 信号，不做任何处理
+func (l *Logger) HandleLevelSignals() (stop func()) { return func() {} }
+
+func HandleLevelSignals() (stop func()) { return std.HandleLevelSignals() }
@@ -0,0 +1,50 @@
+//go:build unix
+
+package zap
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleLevelSignals(t *testing.T) {
+	var buf syncBuffer
+	l := New(&buf, InfoLevel, WithKeyNames(KeyNames{Time: "-"}))
+	stop := l.HandleLevelSignals()
+
+	signalAndWait := func(sig syscall.Signal, want Level) {
+		t.Helper()
+		require.NoError(t, syscall.Kill(syscall.Getpid(), sig))
+		assert.Eventually(t, func() bool { return l.Level() == want }, time.Second, 5*time.Millisecond)
+	}
+	signalAndWait(syscall.SIGUSR1, DebugLevel)
+	signalAndWait(syscall.SIGUSR2, InfoLevel)
+	signalAndWait(syscall.SIGUSR2, WarnLevel)
+	signalAndWait(syscall.SIGUSR2, ErrorLevel)
+	signalAndWait(syscall.SIGUSR2, ErrorLevel)
+
+	assert.Eventually(t, func() bool {
+		return buf.String() == `{"level":"info","msg":"log level changed","from":"info","to":"debug","reason":"user defined signal 1"}
+{"level":"info","msg":"log level changed","from":"debug","to":"info","reason":"user defined signal 2"}
+{"level":"warn","msg":"log level changed","from":"info","to":"warn","reason":"user defined signal 2"}
+{"level":"error","msg":"log level changed","from":"warn","to":"error","reason":"user defined signal 2"}
+`
+	}, time.Second, 5*time.Millisecond, buf.String())
+
+	// 移除信号处理之后不再修改日志级别，测试中另外接收信号避免进程退出
+	stop()
+	stop()
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, syscall.SIGUSR1)
+	defer signal.Stop(c)
+	require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGUSR1))
+	<-c
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, ErrorLevel, l.Level())
+}
@@ -0,0 +1,289 @@
+//go:build go1.21
+
+package zap
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"sort"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// SlogOptions slog.Handler 选项
+type SlogOptions struct {
+	// Level 额外限制的最低日志级别，如 slog.LevelVar，默认只使用 Logger 自身的日志级别
+	Level slog.Leveler
+}
+
+// NewSlogHandler 创建写入 l 的 slog.Handler，日志级别、输出、编码器、轮转等均沿用 l 的配置，
+// 通过 slog.SetDefault(slog.New(h)) 设置为默认 Handler 后，slog 以及标准库 log 包的日志都会写入 l
+func NewSlogHandler(l *Logger, opts *SlogOptions) slog.Handler {
+	h := &slogHandler{l: l.l}
+	if opts != nil {
+		h.level = opts.Level
+	}
+	return h
+}
+
+type slogHandler struct {
+	l     *zap.Logger
+	level slog.Leveler
+	// groups 通过 WithGroup 打开、但还没有字段的分组，没有字段的分组会被忽略
+	groups []string
+}
+
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	if h.level != nil && level < h.level.Level() {
+		return false
+	}
+	return h.l.Core().Enabled(LevelFromSlog(level))
+}
+
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	if h.level != nil && r.Level < h.level.Level() {
+		return nil
+	}
+	ce := h.l.Check(LevelFromSlog(r.Level), r.Message)
+	if ce == nil {
+		return nil
+	}
+	if !r.Time.IsZero() {
+		ce.Time = r.Time
+	}
+	if ce.Caller.Defined && r.PC != 0 {
+		// 调用位置为调用 slog 的用户代码
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		ce.Caller = zapcore.EntryCaller{Defined: true, PC: frame.PC, File: frame.File, Line: frame.Line, Function: frame.Function}
+	}
+
+	fields := ContextFields(ctx)
+	if r.NumAttrs() > 0 {
+		fields = h.openGroups(fields)
+		r.Attrs(func(a slog.Attr) bool {
+			fields = appendAttr(fields, a)
+			return true
+		})
+	}
+	ce.Write(fields...)
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var fields []Field
+	for _, a := range attrs {
+		fields = appendAttr(fields, a)
+	}
+	if len(fields) == 0 {
+		return h
+	}
+	return &slogHandler{l: h.l.With(h.openGroups(nil)...).With(fields...), level: h.level}
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	c := *h
+	c.groups = append(h.groups[:len(h.groups):len(h.groups)], name)
+	return &c
+}
+
+// openGroups 打开还没有字段的分组，之后的字段都会写入分组中
+func (h *slogHandler) openGroups(fields []Field) []Field {
+	for _, g := range h.groups {
+		fields = append(fields, zap.Namespace(g))
+	}
+	return fields
+}
+
+// appendAttr 将 slog.Attr 转换为字段，空的 Attr 以及没有字段的分组会被忽略，键为空的分组会被展开
+func appendAttr(fields []Field, a slog.Attr) []Field {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return fields
+	}
+	switch v := a.Value; v.Kind() {
+	case slog.KindString:
+		return append(fields, zap.String(a.Key, v.String()))
+	case slog.KindInt64:
+		return append(fields, zap.Int64(a.Key, v.Int64()))
+	case slog.KindUint64:
+		return append(fields, zap.Uint64(a.Key, v.Uint64()))
+	case slog.KindFloat64:
+		return append(fields, zap.Float64(a.Key, v.Float64()))
+	case slog.KindBool:
+		return append(fields, zap.Bool(a.Key, v.Bool()))
+	case slog.KindDuration:
+		return append(fields, zap.Duration(a.Key, v.Duration()))
+	case slog.KindTime:
+		return append(fields, zap.Time(a.Key, v.Time()))
+	case slog.KindGroup:
+		attrs := v.Group()
+		if len(attrs) == 0 {
+			return fields
+		}
+		if a.Key == "" {
+			for _, ga := range attrs {
+				fields = appendAttr(fields, ga)
+			}
+			return fields
+		}
+		return append(fields, zap.Object(a.Key, slogGroup(attrs)))
+	}
+	return append(fields, zap.Any(a.Key, a.Value.Any()))
+}
+
+// slogGroup 将 slog 分组编码为嵌套对象
+type slogGroup []slog.Attr
+
+func (g slogGroup) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, f := range appendAttr(nil, slog.Attr{Value: slog.GroupValue(g...)}) {
+		f.AddTo(enc)
+	}
+	return nil
+}
+
+// NewSlog 创建写入 slog.Handler 的 Logger，用于将日志交由已有的 slog.Handler 处理，
+// 编码器相关选项会被忽略，Logger 名称以及堆栈分别以 KeyNames 中 Name、Stacktrace 的键作为字段写入
+func NewSlog(h slog.Handler, level Level, opts ...Option) *Logger {
+	al := zap.NewAtomicLevelAt(level)
+	cfg, opts := splitOptions(opts)
+	var core zapcore.Core = &slogCore{h: h, nameKey: cfg.NameKey, stacktraceKey: cfg.StacktraceKey}
+	if cfg.redactor != nil {
+		core = &redactCore{Core: core, r: cfg.redactor}
+	}
+	return newLogger(cfg.wrap(core), newLevelRegistry(&al), opts)
+}
+
+// slogCore 将日志写入 slog.Handler 的 Core
+type slogCore struct {
+	h             slog.Handler
+	nameKey       string
+	stacktraceKey string
+}
+
+func (c *slogCore) Enabled(level Level) bool {
+	return c.h.Enabled(context.Background(), SlogLevel(level))
+}
+
+func (c *slogCore) With(fields []Field) zapcore.Core {
+	clone := *c
+	clone.h = withFields(c.h, fields)
+	return &clone
+}
+
+// withFields 将字段附加到 h，zap.Namespace 对应 slog 分组
+func withFields(h slog.Handler, fields []Field) slog.Handler {
+	for i, f := range fields {
+		if f.Type == zapcore.NamespaceType {
+			if attrs := fieldsToAttrs(fields[:i]); len(attrs) > 0 {
+				h = h.WithAttrs(attrs)
+			}
+			return withFields(h.WithGroup(f.Key), fields[i+1:])
+		}
+	}
+	if attrs := fieldsToAttrs(fields); len(attrs) > 0 {
+		h = h.WithAttrs(attrs)
+	}
+	return h
+}
+
+func (c *slogCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *slogCore) Write(ent zapcore.Entry, fields []Field) error {
+	var pc uintptr
+	if ent.Caller.Defined {
+		pc = ent.Caller.PC
+	}
+	r := slog.NewRecord(ent.Time, SlogLevel(ent.Level), ent.Message, pc)
+	if ent.LoggerName != "" && c.nameKey != "" && c.nameKey != zapcore.OmitKey {
+		r.AddAttrs(slog.String(c.nameKey, ent.LoggerName))
+	}
+	if ent.Stack != "" && c.stacktraceKey != "" && c.stacktraceKey != zapcore.OmitKey {
+		r.AddAttrs(slog.String(c.stacktraceKey, ent.Stack))
+	}
+	r.AddAttrs(fieldsToAttrs(fields)...)
+	return c.h.Handle(context.Background(), r)
+}
+
+func (c *slogCore) Sync() error { return nil }
+
+// fieldsToAttrs 将字段转换为 slog.Attr，zap.Namespace 之后的字段放入以其命名的分组中
+func fieldsToAttrs(fields []Field) []slog.Attr {
+	attrs := make([]slog.Attr, 0, len(fields))
+	for i, f := range fields {
+		if f.Type == zapcore.NamespaceType {
+			if rest := fieldsToAttrs(fields[i+1:]); len(rest) > 0 {
+				attrs = append(attrs, slog.Attr{Key: f.Key, Value: slog.GroupValue(rest...)})
+			}
+			return attrs
+		}
+		if f.Type == zapcore.SkipType {
+			continue
+		}
+		enc := zapcore.NewMapObjectEncoder()
+		f.AddTo(enc)
+		for _, k := range sortedKeys(enc.Fields) {
+			attrs = append(attrs, slog.Attr{Key: k, Value: slogValue(enc.Fields[k])})
+		}
+	}
+	return attrs
+}
+
+// slogValue 将 zapcore.MapObjectEncoder 编码的值转换为 slog.Value，对象转换为分组
+func slogValue(v interface{}) slog.Value {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return slog.AnyValue(v)
+	}
+	attrs := make([]slog.Attr, 0, len(m))
+	for _, k := range sortedKeys(m) {
+		attrs = append(attrs, slog.Attr{Key: k, Value: slogValue(m[k])})
+	}
+	return slog.GroupValue(attrs...)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// LevelFromSlog 将 slog 日志级别转换为最接近的不高于它的日志级别，低于 slog.LevelInfo 的级别均为 DebugLevel
+func LevelFromSlog(level slog.Level) Level {
+	switch {
+	case level < slog.LevelInfo:
+		return DebugLevel
+	case level < slog.LevelWarn:
+		return InfoLevel
+	case level < slog.LevelError:
+		return WarnLevel
+	}
+	return ErrorLevel
+}
+
+// SlogLevel 将日志级别转换为 slog 日志级别，Panic、Fatal 级别在 slog.LevelError 的基础上递增
+func SlogLevel(level Level) slog.Level {
+	switch level {
+	case DebugLevel:
+		return slog.LevelDebug
+	case InfoLevel:
+		return slog.LevelInfo
+	case WarnLevel:
+		return slog.LevelWarn
+	case ErrorLevel:
+		return slog.LevelError
+	}
+	return slog.LevelError + slog.Level(level-ErrorLevel)
+}
@@ -0,0 +1,141 @@
+//go:build go1.22
+
+package zap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"testing/slogtest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	slogtest.Run(t, func(t *testing.T) slog.Handler {
+		if strings.Contains(t.Name(), "zero-time") {
+			// zap 始终记录日志时间
+			t.Skip("zero Record.Time is not ignored")
+		}
+		buf.Reset()
+		l := New(&buf, DebugLevel, WithKeyNames(KeyNames{Time: slog.TimeKey, Message: slog.MessageKey}))
+		return NewSlogHandler(l, nil)
+	}, func(t *testing.T) map[string]any {
+		var m map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &m))
+		return m
+	})
+}
+
+func TestSlogHandlerLevel(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf, InfoLevel, WithKeyNames(KeyNames{Time: "-"}))
+	lv := new(slog.LevelVar)
+	sl := slog.New(NewSlogHandler(l, &SlogOptions{Level: lv}))
+
+	sl.Debug("debug")
+	sl.Info("info")
+	sl.Log(context.Background(), slog.LevelWarn+2, "warn+2")
+	sl.Log(context.Background(), slog.LevelError+4, "error+4")
+	assert.Equal(t, `{"level":"info","msg":"info"}
+{"level":"warn","msg":"warn+2"}
+{"level":"error","msg":"error+4"}
+`, buf.String())
+
+	// 日志级别同时受 Logger 以及 SlogOptions.Level 控制
+	buf.Reset()
+	assert.False(t, sl.Enabled(context.Background(), slog.LevelDebug))
+	l.SetLevel(DebugLevel)
+	assert.False(t, sl.Enabled(context.Background(), slog.LevelDebug))
+	lv.Set(slog.LevelDebug)
+	assert.True(t, sl.Enabled(context.Background(), slog.LevelDebug))
+	lv.Set(slog.LevelWarn)
+	assert.False(t, sl.Enabled(context.Background(), slog.LevelInfo))
+	sl.Info("info")
+	sl.Warn("warn")
+	assert.Equal(t, `{"level":"warn","msg":"warn"}
+`, buf.String())
+
+	// 按名称设置的日志级别
+	buf.Reset()
+	l.SetNamedLevel("db", ErrorLevel)
+	db := slog.New(NewSlogHandler(l.Named("db"), nil))
+	db.Warn("warn")
+	db.Error("error")
+	assert.Equal(t, `{"level":"error","logger":"db","msg":"error"}
+`, buf.String())
+}
+
+func TestSlogHandlerCallerAndContext(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf, InfoLevel, AddCaller(), WithKeyNames(KeyNames{Time: "-", Level: "-"}))
+	sl := slog.New(NewSlogHandler(l, nil)).WithGroup("g")
+
+	ctx := ContextWithRequestID(context.Background(), "req-1")
+	sl.InfoContext(ctx, "msg", "a", 1)
+	sl.InfoContext(ctx, "empty")
+	assert.Regexp(t, `^{"caller":"zap/slog_test.go:\d+","msg":"msg","requestID":"req-1","g":{"a":1}}
+{"caller":"zap/slog_test.go:\d+","msg":"empty","requestID":"req-1"}
+$`, buf.String())
+}
+
+func TestSlogHandlerAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf, InfoLevel, WithKeyNames(KeyNames{Time: "-", Level: "-"}))
+	sl := slog.New(NewSlogHandler(l, nil))
+
+	sl.Info("msg",
+		"str", "s", "int", -1, "uint", uint64(2), "float", 1.5, "bool", true,
+		"duration", time.Second, "time", time.Date(2023, 3, 19, 21, 57, 59, 0, time.UTC),
+		"error", errors.New("failed"), "slice", []int{1, 2},
+		slog.Group("group", "a", 1, slog.Group("nested", "b", 2)),
+	)
+	assert.Equal(t, `{"msg":"msg","str":"s","int":-1,"uint":2,"float":1.5,"bool":true,"duration":1,"time":"2023-03-19T21:57:59Z","error":"failed","slice":[1,2],"group":{"a":1,"nested":{"b":2}}}
+`, buf.String())
+}
+
+func TestNewSlog(t *testing.T) {
+	var buf bytes.Buffer
+	h := slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	l := NewSlog(h, InfoLevel, WithRedaction(RedactRules{Keys: []string{"password"}}))
+
+	l.Debug("debug")
+	l.Named("db").With(String("addr", "127.0.0.1:3306"), Namespace("query")).
+		Warn("slow", String("sql", "SELECT 1"), Duration("latency", time.Second), Namespace("args"), Int("id", 1))
+	l.Error("login failed", String("password", "123456"), Any("user", map[string]interface{}{"name": "admin"}))
+	assert.Equal(t, `{"level":"WARN","msg":"slow","addr":"127.0.0.1:3306","query":{"logger":"db","sql":"SELECT 1","latency":1000000000,"args":{"id":1}}}
+{"level":"ERROR","msg":"login failed","password":"******","user":{"name":"admin"}}
+`, buf.String())
+
+	// 日志级别由 Logger 控制
+	buf.Reset()
+	l.SetLevel(DebugLevel)
+	l.Debug("debug")
+	assert.Equal(t, `{"level":"DEBUG","msg":"debug"}
+`, buf.String())
+}
+
+func TestSlogLevel(t *testing.T) {
+	for _, level := range []Level{DebugLevel, InfoLevel, WarnLevel, ErrorLevel} {
+		assert.Equal(t, level, LevelFromSlog(SlogLevel(level)))
+	}
+	assert.Equal(t, slog.LevelError+2, SlogLevel(PanicLevel))
+	assert.Equal(t, DebugLevel, LevelFromSlog(slog.LevelDebug-4))
+	assert.Equal(t, InfoLevel, LevelFromSlog(slog.LevelInfo+2))
+	assert.Equal(t, ErrorLevel, LevelFromSlog(slog.LevelError+8))
+}
@@ -0,0 +1,67 @@
+package zap
+
+// 类似 fmt.Printf 以及使用键值对记录字段的 API，基于 zap.SugaredLogger 实现
+// 相比 Debug、Info 等方法会有额外的性能开销，适用于对性能不敏感的场景
+
+func (l *Logger) Debugf(template string, args ...interface{}) {
+	l.s.Debugf(template, args...)
+}
+
+func (l *Logger) Infof(template string, args ...interface{}) {
+	l.s.Infof(template, args...)
+}
+
+func (l *Logger) Warnf(template string, args ...interface{}) {
+	l.s.Warnf(template, args...)
+}
+
+func (l *Logger) Errorf(template string, args ...interface{}) {
+	l.s.Errorf(template, args...)
+}
+
+func (l *Logger) Panicf(template string, args ...interface{}) {
+	l.s.Panicf(template, args...)
+}
+
+func (l *Logger) Fatalf(template string, args ...interface{}) {
+	l.s.Fatalf(template, args...)
+}
+
+// Debugw 使用键值对记录字段，如 Debugw("msg", "key", "value", "attempt", 3)
+func (l *Logger) Debugw(msg string, keysAndValues ...interface{}) {
+	l.s.Debugw(msg, keysAndValues...)
+}
+
+func (l *Logger) Infow(msg string, keysAndValues ...interface{}) {
+	l.s.Infow(msg, keysAndValues...)
+}
+
+func (l *Logger) Warnw(msg string, keysAndValues ...interface{}) {
+	l.s.Warnw(msg, keysAndValues...)
+}
+
+func (l *Logger) Errorw(msg string, keysAndValues ...interface{}) {
+	l.s.Errorw(msg, keysAndValues...)
+}
+
+func (l *Logger) Panicw(msg string, keysAndValues ...interface{}) {
+	l.s.Panicw(msg, keysAndValues...)
+}
+
+func (l *Logger) Fatalw(msg string, keysAndValues ...interface{}) {
+	l.s.Fatalw(msg, keysAndValues...)
+}
+
+func Debugf(template string, args ...interface{}) { std.s.Debugf(template, args...) }
+func Infof(template string, args ...interface{})  { std.s.Infof(template, args...) }
+func Warnf(template string, args ...interface{})  { std.s.Warnf(template, args...) }
+func Errorf(template string, args ...interface{}) { std.s.Errorf(template, args...) }
+func Panicf(template string, args ...interface{}) { std.s.Panicf(template, args...) }
+func Fatalf(template string, args ...interface{}) { std.s.Fatalf(template, args...) }
+
+func Debugw(msg string, keysAndValues ...interface{}) { std.s.Debugw(msg, keysAndValues...) }
+func Infow(msg string, keysAndValues ...interface{})  { std.s.Infow(msg, keysAndValues...) }
+func Warnw(msg string, keysAndValues ...interface{})  { std.s.Warnw(msg, keysAndValues...) }
+func Errorw(msg string, keysAndValues ...interface{}) { std.s.Errorw(msg, keysAndValues...) }
+func Panicw(msg string, keysAndValues ...interface{}) { std.s.Panicw(msg, keysAndValues...) }
+func Fatalw(msg string, keysAndValues ...interface{}) { std.s.Fatalw(msg, keysAndValues...) }
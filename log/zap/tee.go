@@ -12,21 +12,31 @@ type LevelEnablerFunc func(Level) bool
 type TeeOption struct {
 	Out io.Writer
 	LevelEnablerFunc
+
+	// Name 输出名称，可以通过 Logger.SetOutputLevels 在运行时修改该输出的日志级别范围
+	// 名称相同的输出共用同一个日志级别范围
+	Name string
+	// Levels 输出的日志级别范围，未设置时为 AllLevels，同时设置了 LevelEnablerFunc 时需要两者都满足
+	Levels *LevelRange
 }
 
 // NewTee 根据日志级别写入多个输出
 // https://pkg.go.dev/go.uber.org/zap#example-package-AdvancedConfiguration
 func NewTee(tees []TeeOption, opts ...Option) *Logger {
 	var cores []zapcore.Core
+	cfg, opts := splitOptions(opts)
+	outputs := make(outputLevels)
 	for _, tee := range tees {
-		cfg := zap.NewProductionEncoderConfig()
-		cfg.EncodeTime = zapcore.RFC3339TimeEncoder
-		core := zapcore.NewCore(
-			zapcore.NewJSONEncoder(cfg),
-			zapcore.AddSync(tee.Out),
-			zap.LevelEnablerFunc(tee.LevelEnablerFunc),
-		)
-		cores = append(cores, core)
+		var enabler andEnabler
+		if tee.Name != "" || tee.Levels != nil {
+			enabler = append(enabler, outputs.add(tee.Name, tee.Levels))
+		}
+		if tee.LevelEnablerFunc != nil {
+			enabler = append(enabler, zap.LevelEnablerFunc(tee.LevelEnablerFunc))
+		}
+		cores = append(cores, cfg.outputCore(tee.Out, enabler))
 	}
-	return &Logger{l: zap.New(zapcore.NewTee(cores...), opts...)}
+	l := newLogger(cfg.wrap(zapcore.NewTee(cores...)), newLevelRegistry(nil), opts)
+	l.outputs = outputs
+	return l
 }